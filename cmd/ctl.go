@@ -0,0 +1,126 @@
+// Package cmd contains the command line interface for the app.
+//
+// Example:
+//
+//	$ ./gohabit ctl status
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hasnpr/gohabit/pkg/simplevisor"
+	"github.com/spf13/cobra"
+)
+
+// ctlTimeout bounds a single control request, actions wait for processes to exit
+const ctlTimeout = time.Minute
+
+// ctlCmd represents the ctl command of the app.
+// Its subcommands silence the usage, their errors come from the running app.
+var (
+	ctlCmd = &cobra.Command{
+		Use:   "ctl",
+		Short: "Control the processes of a running app",
+		Long:  `Control the supervised processes of a running app through its control socket, like supervisorctl`,
+	}
+
+	ctlStatusCmd = &cobra.Command{
+		Use:          "status",
+		Short:        "Show the status of all processes",
+		Args:         cobra.NoArgs,
+		RunE:         ctlStatus,
+		SilenceUsage: true,
+	}
+
+	ctlTailCmd = &cobra.Command{
+		Use:          "tail",
+		Short:        "Stream process events until interrupted",
+		Args:         cobra.NoArgs,
+		RunE:         ctlTail,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ctlCmd.AddCommand(ctlStatusCmd, ctlTailCmd)
+
+	for _, action := range []struct {
+		command string
+		short   string
+	}{
+		{simplevisor.ControlRestart, "Restart a process with a fresh restart budget"},
+		{simplevisor.ControlStop, "Stop a process until it is restarted"},
+		{simplevisor.ControlPause, "Pause a process until it is resumed"},
+		{simplevisor.ControlResume, "Resume a paused process"},
+	} {
+		ctlCmd.AddCommand(&cobra.Command{
+			Use:          action.command + " <name>",
+			Short:        action.short,
+			Args:         cobra.ExactArgs(1),
+			RunE:         ctlAction(action.command),
+			SilenceUsage: true,
+		})
+	}
+
+	rootCmd.AddCommand(ctlCmd)
+}
+
+func ctlStatus(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), ctlTimeout)
+	defer cancel()
+
+	processes, err := simplevisor.NewControlClient(controlSocket).Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tRESTARTS\tUPTIME\tCHILDREN\tGOROUTINES\tLAST ERROR")
+	for _, p := range processes {
+		uptime := "-"
+		if p.Status == simplevisor.StatusRunning.String() && !p.StartedAt.IsZero() {
+			uptime = time.Since(p.StartedAt).Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%d\t%s\n", p.Name, p.Status, p.RestartCount, uptime, p.Children,
+			p.Goroutines, p.LastError)
+	}
+
+	return w.Flush()
+}
+
+func ctlAction(command string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(cmd.Context(), ctlTimeout)
+		defer cancel()
+
+		if err := simplevisor.NewControlClient(controlSocket).Do(ctx, command, args[0]); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %s done\n", args[0], command)
+		return nil
+	}
+}
+
+func ctlTail(cmd *cobra.Command, _ []string) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return simplevisor.NewControlClient(controlSocket).Tail(ctx, func(e simplevisor.ControlEvent) {
+		line := []string{e.Time.Format(time.RFC3339), e.Type}
+		if e.Process != "" {
+			line = append(line, e.Process)
+		}
+		if e.Error != "" {
+			line = append(line, e.Error)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), strings.Join(line, " "))
+	})
+}
@@ -7,6 +7,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/hasnpr/gohabit/internal/app"
 	"github.com/spf13/cobra"
@@ -14,8 +16,9 @@ import (
 
 var (
 	// Flag variables
-	cfgPath    string
-	showBanner bool
+	cfgPath       string
+	showBanner    bool
+	controlSocket string
 
 	// rootCMD represents the base command when called without any subcommands
 	rootCmd = &cobra.Command{
@@ -30,6 +33,22 @@ var (
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgPath, "config", "", "config file path")
 	rootCmd.PersistentFlags().BoolVar(&showBanner, "show-banner", false, "show application banner")
+	rootCmd.PersistentFlags().StringVar(&controlSocket, "control-socket",
+		defaultControlSocket(), "supervisor control socket path, empty to disable")
+}
+
+// defaultControlSocket returns a per-user control socket path, never a shared one like /tmp:
+// gohabit.sock in $XDG_RUNTIME_DIR or else in the user cache directory.
+func defaultControlSocket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gohabit.sock")
+	}
+
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "gohabit", "gohabit.sock")
+	}
+
+	return ""
 }
 
 func preRun(_ *cobra.Command, _ []string) {
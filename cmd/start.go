@@ -16,7 +16,7 @@ var (
 		Use:   "start",
 		Short: "A sample app that can be used",
 		Long:  `A simple app that can be used as a sample that has redis, mariadb and tracing and logging`,
-		Run:   start,
+		RunE:  start,
 	}
 )
 
@@ -24,6 +24,6 @@ func init() {
 	rootCmd.AddCommand(startCmd)
 }
 
-func start(_ *cobra.Command, _ []string) {
-	app.Start()
+func start(_ *cobra.Command, _ []string) error {
+	return app.Start(controlSocket)
 }
@@ -2,13 +2,25 @@ package app
 
 import (
 	"log/slog"
+
+	"github.com/hasnpr/gohabit/pkg/simplevisor"
 )
 
-func Start() {
+// Start runs the app under a supervisor until a shutdown signal.
+// The supervisor serves its control socket at controlSocket, see the ctl command.
+func Start(controlSocket string) error {
 	slog.Info("app started")
-	// start simplevisor
+
+	supervisor := simplevisor.New(simplevisor.DefaultGracefulShutdownTimeout, slog.Default(),
+		simplevisor.WithControlSocket(controlSocket))
 	// start http server with business-app.WithDB.WithRedis.WithAnalyticsCMQ
-	// handle simplevisor shutdown
+	if err := supervisor.Start(); err != nil {
+		return err
+	}
+
+	supervisor.WaitOnShutdownSignal(nil)
+
+	return nil
 }
 
 // ctx := context.Background()
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/hasnpr/gohabit/pkg/simplevisor"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -57,3 +58,32 @@ func CMQTypeDecodeHook() mapstructure.DecodeHookFunc {
 		return nil, fmt.Errorf("invalid cmq type")
 	}
 }
+
+// RestartPolicyDecodeHook is a function to transform simplevisor.RestartPolicy values.
+func RestartPolicyDecodeHook() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+
+		var rp simplevisor.RestartPolicy
+		if t != reflect.TypeOf(rp) {
+			return data, nil
+		}
+
+		str, ok := data.(string)
+		if !ok {
+			return data, nil
+		}
+
+		if r, ok := map[string]simplevisor.RestartPolicy{
+			simplevisor.RestartNever.String():     simplevisor.RestartNever,
+			simplevisor.RestartAlways.String():    simplevisor.RestartAlways,
+			simplevisor.RestartOnFailure.String(): simplevisor.RestartOnFailure,
+		}[str]; ok {
+			return r, nil
+		}
+
+		return nil, fmt.Errorf("invalid restart policy")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hasnpr/gohabit/pkg/simplevisor"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -197,6 +198,91 @@ func TestCMQTypeDecodeHook(t *testing.T) {
 	}
 }
 
+func TestRestartPolicyDecodeHook(t *testing.T) {
+	hook := RestartPolicyDecodeHook()
+
+	tests := []struct {
+		name        string
+		from        reflect.Type
+		to          reflect.Type
+		data        interface{}
+		expected    interface{}
+		expectError bool
+	}{
+		{
+			name:        "Valid never",
+			from:        reflect.TypeOf(""),
+			to:          reflect.TypeOf(simplevisor.RestartPolicy(0)),
+			data:        "never",
+			expected:    simplevisor.RestartNever,
+			expectError: false,
+		},
+		{
+			name:        "Valid always",
+			from:        reflect.TypeOf(""),
+			to:          reflect.TypeOf(simplevisor.RestartPolicy(0)),
+			data:        "always",
+			expected:    simplevisor.RestartAlways,
+			expectError: false,
+		},
+		{
+			name:        "Valid on_failure",
+			from:        reflect.TypeOf(""),
+			to:          reflect.TypeOf(simplevisor.RestartPolicy(0)),
+			data:        "on_failure",
+			expected:    simplevisor.RestartOnFailure,
+			expectError: false,
+		},
+		{
+			name:        "Invalid restart policy",
+			from:        reflect.TypeOf(""),
+			to:          reflect.TypeOf(simplevisor.RestartPolicy(0)),
+			data:        "sometimes",
+			expected:    nil,
+			expectError: true,
+		},
+		{
+			name:        "Non-string input",
+			from:        reflect.TypeOf(123),
+			to:          reflect.TypeOf(simplevisor.RestartPolicy(0)),
+			data:        123,
+			expected:    123,
+			expectError: false,
+		},
+		{
+			name:        "Wrong target type",
+			from:        reflect.TypeOf(""),
+			to:          reflect.TypeOf(""),
+			data:        "always",
+			expected:    "always",
+			expectError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hookFunc := hook.(func(reflect.Type, reflect.Type, interface{}) (interface{}, error))
+			result, err := hookFunc(tt.from, tt.to, tt.data)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("RestartPolicyDecodeHook() expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("RestartPolicyDecodeHook() unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("RestartPolicyDecodeHook() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestHooksIntegration(t *testing.T) {
 	// Test that hooks work with mapstructure
 	type TestConfig struct {
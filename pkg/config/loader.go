@@ -69,6 +69,7 @@ func LoadConfig(
 		mapstructure.StringToSliceHookFunc(","),
 		TimeLocationDecodeHook(),
 		CMQTypeDecodeHook(),
+		RestartPolicyDecodeHook(),
 	}
 	hooks = append(hooks, decodeHookFuncs...)
 
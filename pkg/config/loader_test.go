@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/hasnpr/gohabit/pkg/simplevisor"
 )
 
 // Test configuration structs
@@ -389,3 +391,46 @@ list: "item1,item2,item3"
 		t.Errorf("Config.List = %v, want %v", config.List, expectedList)
 	}
 }
+
+func TestLoadConfig_SimplevisorConfig(t *testing.T) {
+	configYAML := `
+shutdown_timeout: "10s"
+processes:
+  consumer:
+    restart: "on_failure"
+    max_restarts: 5
+    delay: "2s"
+  reporter:
+    restart: "never"
+`
+
+	var config simplevisor.Config
+	err := LoadConfig("TEST", "", []byte(configYAML), &config)
+
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if config.ShutdownTimeout != 10*time.Second {
+		t.Errorf("Config.ShutdownTimeout = %v, want 10s", config.ShutdownTimeout)
+	}
+
+	consumer := config.Processes["consumer"]
+	if consumer.Restart == nil || *consumer.Restart != simplevisor.RestartOnFailure {
+		t.Errorf("consumer.Restart = %v, want on_failure", consumer.Restart)
+	}
+	if consumer.MaxRestarts == nil || *consumer.MaxRestarts != 5 {
+		t.Errorf("consumer.MaxRestarts = %v, want 5", consumer.MaxRestarts)
+	}
+	if consumer.Delay == nil || *consumer.Delay != 2*time.Second {
+		t.Errorf("consumer.Delay = %v, want 2s", consumer.Delay)
+	}
+
+	reporter := config.Processes["reporter"]
+	if reporter.Restart == nil || *reporter.Restart != simplevisor.RestartNever {
+		t.Errorf("reporter.Restart = %v, want never", reporter.Restart)
+	}
+	if reporter.MaxRestarts != nil || reporter.Delay != nil {
+		t.Errorf("reporter has unexpected settings: %+v", reporter)
+	}
+}
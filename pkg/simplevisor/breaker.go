@@ -0,0 +1,151 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// BreakerState represents the state of a process restart circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Restarts are allowed
+	BreakerOpen                         // Restart limit tripped, process is cooling off
+	BreakerHalfOpen                     // A single probing run after the cool-off
+)
+
+func (b BreakerState) String() string {
+	switch b {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// WithCircuitBreaker keeps the process alive once it hits its restart limit.
+// Instead of stopping, the breaker opens for coolOff and then allows a single half-open run.
+// A half-open run which stays up for the healthy duration closes the breaker and resets the restart count.
+// After maxProbes failed half-open runs the process is stopped for good; maxProbes <= 0 means probe forever.
+func WithCircuitBreaker(coolOff time.Duration, maxProbes int) Option {
+	return func(p *Process) {
+		p.breakerCoolOff = coolOff
+		p.breakerMaxProbes = maxProbes
+	}
+}
+
+// startBreakerProbe closes the breaker once a half-open run stays up for the healthy duration.
+// The returned function must be called when the run finishes.
+func (s *Supervisor) startBreakerProbe(name string) func() {
+	if s.getBreakerState(name) != BreakerHalfOpen {
+		return func() {}
+	}
+
+	s.logger.Info("probing process with half-open circuit breaker", slog.String("process_name", name))
+	timer := s.clock.AfterFunc(DefaultHealthyDuration, func() {
+		s.closeBreaker(name)
+	})
+
+	return func() {
+		timer.Stop()
+	}
+}
+
+// openBreaker waits for the cool-off period and moves the breaker to half-open.
+// Returns false if the process context is cancelled or the supervisor starts draining in the meantime.
+func (s *Supervisor) openBreaker(ctx context.Context, name string, process Process) bool {
+	s.setBreakerState(name, BreakerOpen)
+	s.setProcessStatus(name, StatusRestarting)
+	s.logger.Warn("process circuit breaker opened",
+		slog.String("process_name", name),
+		slog.Duration("cool_off", process.breakerCoolOff),
+		slog.Int("restart_count", s.getRestartCount(name)))
+	s.publish(Event{Type: EventBreakerOpened, Process: name, RestartCount: s.getRestartCount(name)})
+
+	select {
+	case <-s.clock.After(process.breakerCoolOff):
+	case <-ctx.Done():
+		return false
+	case <-s.draining:
+		return false
+	}
+
+	s.setBreakerState(name, BreakerHalfOpen)
+	return true
+}
+
+// reopenBreaker handles a failed half-open run.
+// Returns false if the process ran out of probes or its context is cancelled.
+func (s *Supervisor) reopenBreaker(ctx context.Context, name string, process Process) bool {
+	failures := s.incrementProbeFailures(name)
+	if process.breakerMaxProbes > 0 && failures >= process.breakerMaxProbes {
+		s.logger.Error("process failed all half-open probes, giving up",
+			slog.String("process_name", name),
+			slog.Int("probe_failures", failures))
+		s.metrics.recordRestartLimitExceeded(name, process.maxRestarts)
+		s.publish(Event{Type: EventRestartLimitExceeded, Process: name, RestartCount: s.getRestartCount(name)})
+		s.setBreakerState(name, BreakerOpen)
+		s.setProcessStatus(name, StatusStopped)
+		return false
+	}
+
+	return s.openBreaker(ctx, name, process)
+}
+
+// closeBreaker closes the breaker of a process and restores its restart budget
+func (s *Supervisor) closeBreaker(name string) {
+	if s.getBreakerState(name) == BreakerClosed {
+		return
+	}
+
+	s.logger.Info("process circuit breaker closed", slog.String("process_name", name))
+	s.setBreakerState(name, BreakerClosed)
+	s.resetRestartCount(name)
+	s.publish(Event{Type: EventBreakerClosed, Process: name})
+}
+
+// setBreakerState updates the breaker state of a process
+func (s *Supervisor) setBreakerState(name string, state BreakerState) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if process, exists := s.processes[name]; exists && process.breaker != state {
+		process.breaker = state
+		if state == BreakerClosed {
+			process.probeFailures = 0
+		}
+		s.processes[name] = process
+		s.metrics.recordBreakerState(name, state)
+	}
+}
+
+// getBreakerState returns the breaker state of a process
+func (s *Supervisor) getBreakerState(name string) BreakerState {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if process, exists := s.processes[name]; exists {
+		return process.breaker
+	}
+	return BreakerClosed
+}
+
+// incrementProbeFailures increments the failed half-open runs of a process and returns the new count
+func (s *Supervisor) incrementProbeFailures(name string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	process, exists := s.processes[name]
+	if !exists {
+		return 0
+	}
+
+	process.probeFailures++
+	s.processes[name] = process
+	return process.probeFailures
+}
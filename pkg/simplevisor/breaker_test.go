@@ -0,0 +1,88 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_CircuitBreakerGivesUp(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var execCount atomic.Int32
+	var sawOpen atomic.Bool
+	handler := func(ctx context.Context) error {
+		execCount.Add(1)
+		return errors.New("database unavailable")
+	}
+
+	s.Register("breaker-test", handler,
+		WithRestart(RestartOnFailure, 2, 10*time.Millisecond),
+		WithCircuitBreaker(50*time.Millisecond, 2))
+	s.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		info, _ := s.GetProcessInfo("breaker-test")
+		if info.Breaker == BreakerOpen && info.Status == StatusRestarting {
+			sawOpen.Store(true)
+		}
+		if info.Status == StatusStopped && execCount.Load() > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if !sawOpen.Load() {
+		t.Error("Breaker should have opened after the restart limit")
+	}
+
+	// 2 runs to trip the breaker and 2 failed half-open probes
+	if execCount.Load() != 4 {
+		t.Errorf("Expected 4 executions, got %d", execCount.Load())
+	}
+
+	info, _ := s.GetProcessInfo("breaker-test")
+	if info.Status != StatusStopped || info.Breaker != BreakerOpen {
+		t.Errorf("Expected stopped process with open breaker, got %s/%s", info.Status, info.Breaker)
+	}
+
+	s.Shutdown()
+}
+
+func TestSupervisor_CircuitBreakerCloses(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	var execCount atomic.Int32
+	handler := func(ctx context.Context) error {
+		if execCount.Add(1) <= 2 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	}
+
+	s.Register("breaker-close-test", handler,
+		WithRestart(RestartOnFailure, 2, 10*time.Millisecond),
+		WithCircuitBreaker(20*time.Millisecond, 0))
+	s.Run()
+	defer s.Shutdown()
+
+	awaitEvent(t, events, EventBreakerClosed, "breaker-close-test")
+
+	if execCount.Load() != 3 {
+		t.Errorf("Expected 3 executions, got %d", execCount.Load())
+	}
+
+	info, _ := s.GetProcessInfo("breaker-close-test")
+	if info.Breaker != BreakerClosed {
+		t.Errorf("Successful half-open run should close the breaker, got %s", info.Breaker)
+	}
+
+	if info.RestartCount != 0 {
+		t.Errorf("Closing the breaker should reset the restart count, got %d", info.RestartCount)
+	}
+}
@@ -0,0 +1,157 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Faults injected by chaos mode, used in the fault metric attribute
+const (
+	ChaosFaultCancel = "cancel"
+	ChaosFaultPanic  = "panic"
+	ChaosFaultDelay  = "delay"
+)
+
+// ErrChaosCancel is the cancellation cause of a process run cancelled by chaos mode.
+var ErrChaosCancel = errors.New("chaos: run cancelled")
+
+// ChaosConfig configures the faults injected by chaos mode, see WithChaos.
+// Each fault is rolled independently for every run of a selected process.
+type ChaosConfig struct {
+	Seed     int64    // Seed of the random faults, the same seed injects the same sequence of faults
+	Selector Selector // Processes to inject faults into, every process if empty
+
+	CancelProbability float64       // Chance to cancel a run after a random time up to MaxCancelAfter
+	MaxCancelAfter    time.Duration // DefaultHealthyDuration if <= 0
+	PanicProbability  float64       // Chance to panic at the start of a run
+	DelayProbability  float64       // Chance to delay the start of a run by a random time up to MaxDelay
+	MaxDelay          time.Duration // DefaultRestartDelay if <= 0
+}
+
+// chaos injects random faults into process runs.
+type chaos struct {
+	config ChaosConfig
+	lock   sync.Mutex
+	rng    *rand.Rand
+}
+
+// WithChaos enables chaos mode to validate restart policies, e.g. in resilience drills in staging.
+// Runs of the selected processes are randomly cancelled, panicked or delayed as configured.
+// Faults are logged, published as events and recorded as metrics.
+// Cancels and panics are injected by a middleware which always wraps the handler directly,
+// inside the supervisor and process middlewares, whatever Use and SetMiddleware do.
+// Delays are injected before the run starts, so a delayed process isn't running yet.
+// Never enable it in production.
+func WithChaos(config ChaosConfig) SupervisorOption {
+	return func(s *Supervisor) {
+		s.chaos = newChaos(config)
+
+		s.logger.Warn("CHAOS MODE ENABLED: faults will be injected into supervised processes",
+			slog.Int64("seed", config.Seed),
+			slog.Any("selector", config.Selector),
+			slog.Float64("cancel_probability", config.CancelProbability),
+			slog.Float64("panic_probability", config.PanicProbability),
+			slog.Float64("delay_probability", config.DelayProbability))
+	}
+}
+
+func newChaos(config ChaosConfig) *chaos {
+	if config.MaxCancelAfter <= 0 {
+		config.MaxCancelAfter = DefaultHealthyDuration
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = DefaultRestartDelay
+	}
+
+	return &chaos{
+		config: config,
+		rng:    rand.New(rand.NewPCG(uint64(config.Seed), uint64(config.Seed))), // #nosec G404 -- faults, not secrets
+	}
+}
+
+// roll reports whether an event with probability p happens.
+func (c *chaos) roll(p float64) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.rng.Float64() < p
+}
+
+// duration returns a random duration in [0, limit).
+func (c *chaos) duration(limit time.Duration) time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return time.Duration(c.rng.Int64N(int64(limit)))
+}
+
+// delayStart delays the start of a run of process if chaos mode rolls a delay.
+// Returns false if ctx is done or the supervisor starts draining in the meantime.
+func (s *Supervisor) delayStart(ctx context.Context, process Process) bool {
+	c := s.chaos
+	if c == nil || !c.config.Selector.Matches(process.labels) || !c.roll(c.config.DelayProbability) {
+		return true
+	}
+
+	delay := c.duration(c.config.MaxDelay)
+	s.injectFault(process.name, ChaosFaultDelay, EventChaosDelay, nil, slog.Duration("delay", delay))
+
+	select {
+	case <-s.clock.After(delay):
+		return true
+	case <-ctx.Done():
+		return false
+	case <-s.draining:
+		return false
+	}
+}
+
+// middleware injects panics and cancellations into the runs of the selected processes.
+// runProcess applies it innermost, so injected faults go through the whole chain.
+func (c *chaos) middleware(s *Supervisor) Middleware {
+	return func(next ProcessFunc) ProcessFunc {
+		return func(ctx context.Context) error {
+			// Jobs and tasks aren't in the process table, select by the labels of the run
+			run, _ := runInfoFrom(ctx)
+			name := run.name
+
+			if !c.config.Selector.Matches(run.labels) {
+				return next(ctx)
+			}
+
+			if c.roll(c.config.PanicProbability) {
+				s.injectFault(name, ChaosFaultPanic, EventChaosPanic, nil)
+				panic(fmt.Sprintf("chaos: injected panic into %s", name))
+			}
+
+			if c.roll(c.config.CancelProbability) {
+				after := c.duration(c.config.MaxCancelAfter)
+
+				var cancel context.CancelCauseFunc
+				ctx, cancel = context.WithCancelCause(ctx)
+				defer cancel(nil)
+
+				timer := s.clock.AfterFunc(after, func() {
+					s.injectFault(name, ChaosFaultCancel, EventChaosCancel, ErrChaosCancel, slog.Duration("after", after))
+					cancel(ErrChaosCancel)
+				})
+				defer timer.Stop()
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// injectFault logs, publishes and records a fault injected by chaos mode.
+func (s *Supervisor) injectFault(name string, fault string, eventType EventType, err error, attrs ...any) {
+	attrs = append([]any{slog.String("process_name", name), slog.String("fault", fault)}, attrs...)
+	s.logger.Warn("chaos: injecting fault", attrs...)
+	s.metrics.recordChaosFault(name, fault)
+	s.publish(Event{Type: eventType, Process: name, Err: err})
+}
@@ -0,0 +1,207 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newChaosSupervisor(config ChaosConfig) *Supervisor {
+	return New(time.Second, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithChaos(config))
+}
+
+// awaitEvent returns the first event of type for process or fails the test after a second.
+func awaitEvent(t *testing.T, events <-chan Event, eventType EventType, process string) Event {
+	t.Helper()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == eventType && event.Process == process {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("Expected %s event for %s", eventType, process)
+			return Event{}
+		}
+	}
+}
+
+func TestChaos_Panic(t *testing.T) {
+	s := newChaosSupervisor(ChaosConfig{Seed: 1, PanicProbability: 1})
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	awaitEvent(t, events, EventChaosPanic, "worker")
+	event := awaitEvent(t, events, EventPanic, "worker")
+
+	var panicErr *PanicError
+	if !errors.As(event.Err, &panicErr) {
+		t.Errorf("Expected injected panic to be recovered as *PanicError, got %v", event.Err)
+	}
+}
+
+func TestChaos_Cancel(t *testing.T) {
+	s := newChaosSupervisor(ChaosConfig{Seed: 1, CancelProbability: 1, MaxCancelAfter: 20 * time.Millisecond})
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	awaitEvent(t, events, EventChaosCancel, "worker")
+	event := awaitEvent(t, events, EventStopped, "worker")
+	if !errors.Is(event.Err, ErrChaosCancel) {
+		t.Errorf("Expected run to be cancelled by chaos, got %v", event.Err)
+	}
+}
+
+func TestChaos_Delay(t *testing.T) {
+	s := newChaosSupervisor(ChaosConfig{Seed: 1, DelayProbability: 1, MaxDelay: 50 * time.Millisecond})
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	awaitEvent(t, events, EventChaosDelay, "worker")
+	waitForStatus(t, s, "worker", StatusRunning, time.Second)
+}
+
+func TestChaos_DelayBeforeStart(t *testing.T) {
+	s := newChaosSupervisor(ChaosConfig{Seed: 1, DelayProbability: 1, MaxDelay: time.Hour})
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	awaitEvent(t, events, EventChaosDelay, "worker")
+
+	info, _ := s.GetProcessInfo("worker")
+	if info.Status == StatusRunning || info.Attempts != 0 {
+		t.Errorf("Delayed process should not be started yet, got %s after %d attempts", info.Status, info.Attempts)
+	}
+}
+
+func TestChaos_WrapsHandlerDirectly(t *testing.T) {
+	s := newChaosSupervisor(ChaosConfig{Seed: 1, PanicProbability: 1})
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	// Middlewares added after WithChaos still wrap the injected faults
+	var wrapped atomic.Bool
+	s.Use(func(next ProcessFunc) ProcessFunc {
+		return func(ctx context.Context) error {
+			wrapped.Store(true)
+			return next(ctx)
+		}
+	})
+
+	s.Register("worker", func(ctx context.Context) error { return nil })
+	s.Run()
+	defer s.Shutdown()
+
+	awaitEvent(t, events, EventChaosPanic, "worker")
+	if !wrapped.Load() {
+		t.Error("Chaos middleware should run inside the middlewares added with Use")
+	}
+}
+
+func TestChaos_Selector(t *testing.T) {
+	s := newChaosSupervisor(ChaosConfig{Seed: 1, PanicProbability: 1, Selector: Selector{"chaos": "on"}})
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	s.Register("safe", func(ctx context.Context) error { return nil })
+	s.Register("target", func(ctx context.Context) error { return nil },
+		WithLabels(map[string]string{"chaos": "on"}))
+	s.Run()
+	defer s.Shutdown()
+
+	safeStopped, targetPanicked := false, false
+	timeout := time.After(time.Second)
+	for !safeStopped || !targetPanicked {
+		select {
+		case event := <-events:
+			switch {
+			case event.Type == EventStopped && event.Process == "safe":
+				safeStopped = true
+				if event.Err != nil {
+					t.Errorf("Expected unselected process to run without faults, got %v", event.Err)
+				}
+			case event.Type == EventChaosPanic && event.Process == "target":
+				targetPanicked = true
+			case event.Type == EventChaosPanic:
+				t.Errorf("Unexpected fault injected into %s", event.Process)
+			}
+		case <-timeout:
+			t.Fatal("Expected safe to stop and target to panic")
+		}
+	}
+}
+
+func TestChaos_SelectsJobsByLabels(t *testing.T) {
+	s := newChaosSupervisor(ChaosConfig{Seed: 1, PanicProbability: 1, Selector: Selector{"chaos": "on"}})
+
+	safe := s.Submit("safe", func(ctx context.Context) error { return nil })
+	target := s.Submit("target", func(ctx context.Context) error { return nil },
+		WithLabels(map[string]string{"chaos": "on"}))
+	defer s.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := safe.Wait(ctx); err != nil {
+		t.Errorf("Expected unselected job to run without faults, got %v", err)
+	}
+
+	var panicErr *PanicError
+	if err := target.Wait(ctx); !errors.As(err, &panicErr) {
+		t.Errorf("Expected chaos panic in the labelled job, got %v", err)
+	}
+}
+
+func TestChaos_Seed(t *testing.T) {
+	rolls := func(seed int64) []bool {
+		c := newChaos(ChaosConfig{Seed: seed})
+
+		var result []bool
+		for range 32 {
+			result = append(result, c.roll(0.5))
+		}
+		return result
+	}
+
+	first, second := rolls(42), rolls(42)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatal("Expected the same seed to roll the same faults")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Cleanup registers fn to be called when the current run of the process ends, whether it returns,
+// fails, panics or is stopped. Like testing.T.Cleanup, functions are called in last added, first called order.
+// Errors are logged and attached to the ExitRecord of the run, they don't fail the run.
+// Outside a supervised process run, or once the run has ended, fn is called right away.
+func Cleanup(ctx context.Context, fn func() error) {
+	run, ok := runInfoFrom(ctx)
+	if !ok || run.cleanups == nil || !run.cleanups.add(fn) {
+		if err := callCleanup(fn); err != nil {
+			Logger(ctx).Error("cleanup failed", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// cleanupStack holds the Cleanup functions of a run.
+type cleanupStack struct {
+	lock  sync.Mutex
+	funcs []func() error
+	done  bool  // The functions have been called
+	err   error // Joined errors of the functions
+}
+
+// add pushes fn, it reports false if the run has already ended.
+func (c *cleanupStack) add(fn func() error) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.done {
+		return false
+	}
+
+	c.funcs = append(c.funcs, fn)
+	return true
+}
+
+// cleanupErr returns the joined errors of the Cleanup functions.
+func (c *cleanupStack) cleanupErr() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.err
+}
+
+// runCleanups calls the Cleanup functions of a run in reverse order and logs their errors.
+func (s *Supervisor) runCleanups(run runInfo) {
+	c := run.cleanups
+
+	c.lock.Lock()
+	funcs := c.funcs
+	c.funcs = nil
+	c.done = true
+	c.lock.Unlock()
+
+	var errs []error
+	for i := len(funcs) - 1; i >= 0; i-- {
+		if err := callCleanup(funcs[i]); err != nil {
+			s.logger.Error("process cleanup failed",
+				slog.String("process_name", run.name),
+				slog.Int("attempt", run.attempt),
+				slog.String("error", err.Error()))
+			errs = append(errs, err)
+		}
+	}
+
+	c.lock.Lock()
+	c.err = errors.Join(errs...)
+	c.lock.Unlock()
+}
+
+// callCleanup calls fn and turns a panic into an error, so every cleanup function gets called.
+func callCleanup(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cleanup panicked: %v", r)
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,196 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCleanup_RunsInReverseOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(ctx context.Context) error
+		stop    bool // Stop the process instead of waiting for it to return
+	}{
+		{
+			name:    "return",
+			handler: func(ctx context.Context) error { return nil },
+		},
+		{
+			name:    "error",
+			handler: func(ctx context.Context) error { return errors.New("failed") },
+		},
+		{
+			name:    "panic",
+			handler: func(ctx context.Context) error { panic("boom") },
+		},
+		{
+			name: "stop",
+			handler: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+			stop: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := createTestSupervisor(time.Second)
+
+			var lock sync.Mutex
+			var calls []string
+			registered := make(chan struct{})
+			s.Register("process", func(ctx context.Context) error {
+				for _, name := range []string{"first", "second", "third"} {
+					Cleanup(ctx, func() error {
+						lock.Lock()
+						defer lock.Unlock()
+						calls = append(calls, name)
+						return nil
+					})
+				}
+				close(registered)
+				return tt.handler(ctx)
+			})
+			s.Run()
+
+			<-registered
+			if tt.stop {
+				waitForStatus(t, s, "process", StatusRunning, time.Second)
+			}
+			s.Shutdown()
+
+			lock.Lock()
+			defer lock.Unlock()
+			if expected := []string{"third", "second", "first"}; !slices.Equal(calls, expected) {
+				t.Errorf("expected cleanups %v, got %v", expected, calls)
+			}
+		})
+	}
+}
+
+func TestCleanup_ErrorsAreAttachedToExitRecord(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	closeErr := errors.New("close failed")
+	var calledAfterPanic bool
+	s.Register("process", func(ctx context.Context) error {
+		Cleanup(ctx, func() error {
+			calledAfterPanic = true
+			return closeErr
+		})
+		Cleanup(ctx, func() error {
+			panic("cleanup boom")
+		})
+		return nil
+	})
+	s.Run()
+
+	var exits []ExitRecord
+	deadline := time.Now().Add(time.Second)
+	for len(exits) == 0 && time.Now().Before(deadline) {
+		info, _ := s.GetProcessInfo("process")
+		exits = info.Exits
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.Shutdown()
+
+	if len(exits) != 1 {
+		t.Fatalf("expected 1 exit, got %d", len(exits))
+	}
+	if exits[0].Err != nil {
+		t.Errorf("expected cleanup errors not to fail the run, got %v", exits[0].Err)
+	}
+	if !errors.Is(exits[0].CleanupErr, closeErr) {
+		t.Errorf("expected cleanup error in exit record, got %v", exits[0].CleanupErr)
+	}
+	if !calledAfterPanic {
+		t.Error("expected cleanup after a panicking cleanup to be called")
+	}
+}
+
+func TestCleanup_EachRunHasItsOwnCleanups(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var lock sync.Mutex
+	var calls []int
+	s.Register("process", func(ctx context.Context) error {
+		attempt := Attempt(ctx)
+		Cleanup(ctx, func() error {
+			lock.Lock()
+			defer lock.Unlock()
+			calls = append(calls, attempt)
+			return nil
+		})
+		if attempt < 3 {
+			return errors.New("failed")
+		}
+		<-ctx.Done()
+		return nil
+	}, WithRestart(RestartOnFailure, 5, 10*time.Millisecond))
+	s.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if info, _ := s.GetProcessInfo("process"); info.Attempts == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	lock.Lock()
+	if !slices.Equal(calls, []int{1, 2}) {
+		t.Errorf("expected cleanups of runs 1 and 2 before shutdown, got %v", calls)
+	}
+	lock.Unlock()
+
+	s.Shutdown()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if !slices.Equal(calls, []int{1, 2, 3}) {
+		t.Errorf("expected cleanups of all runs after shutdown, got %v", calls)
+	}
+}
+
+func TestCleanup_OutsideProcessRunsImmediately(t *testing.T) {
+	called := false
+	Cleanup(context.Background(), func() error {
+		called = true
+		return nil
+	})
+
+	if !called {
+		t.Error("expected cleanup outside a process to be called right away")
+	}
+}
+
+func TestCleanup_Job(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	defer s.Shutdown()
+
+	called := make(chan struct{})
+	job := s.Submit("job", func(ctx context.Context) error {
+		Cleanup(ctx, func() error {
+			close(called)
+			return nil
+		})
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := job.Wait(ctx); err != nil {
+		t.Fatalf("unexpected job error: %v", err)
+	}
+
+	select {
+	case <-called:
+	default:
+		t.Error("expected cleanup of the job to be called")
+	}
+}
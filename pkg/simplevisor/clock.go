@@ -0,0 +1,39 @@
+package simplevisor
+
+import "time"
+
+// Clock is the source of time for the supervisor.
+// All restart delays, healthy durations and shutdown timeouts are measured with it,
+// so tests can replace it with a fake clock, see the simplevisortest package.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending call created by Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the call from firing. It returns false if the call already fired or was stopped.
+	Stop() bool
+}
+
+// WithClock replaces the real clock of the supervisor.
+func WithClock(clock Clock) SupervisorOption {
+	return func(s *Supervisor) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
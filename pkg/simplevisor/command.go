@@ -0,0 +1,182 @@
+//go:build unix
+
+package simplevisor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultCommandGracePeriod is the time between SIGTERM and SIGKILL when a command is stopped.
+	// During shutdown it is cut short to kill the command within the shutdown timeout.
+	DefaultCommandGracePeriod = 5 * time.Second
+	// maxCommandLineLength caps a single logged output line of a command
+	maxCommandLineLength = 64 * 1024
+)
+
+// CommandSpec describes an external OS command supervised with RegisterCommand.
+type CommandSpec struct {
+	Path        string
+	Args        []string
+	Env         []string      // Added to the environment of the supervisor
+	Dir         string        // Working directory, the supervisor's if empty
+	GracePeriod time.Duration // Time between SIGTERM and SIGKILL on stop, capped by the shutdown timeout
+}
+
+// RegisterCommand registers an external OS command as a process.
+// The command runs in its own process group; its stdout and stderr are logged line by line.
+// When the process is stopped the group receives SIGTERM and, after the grace period, SIGKILL.
+// On shutdown the SIGTERM is only sent after the drain period, see WithDrainPeriod, and the grace
+// period is cut short so the command is killed before the shutdown timeout.
+// A non-zero exit code is a failure under the RestartPolicy of the process.
+// Panics if the name isn't unique.
+func (s *Supervisor) RegisterCommand(name string, spec CommandSpec, options ...Option) {
+	if spec.GracePeriod <= 0 {
+		spec.GracePeriod = DefaultCommandGracePeriod
+	}
+
+	s.Register(name, s.commandHandler(spec), options...)
+}
+
+func (s *Supervisor) commandHandler(spec CommandSpec) ProcessFunc {
+	return func(ctx context.Context) error {
+		logger := Logger(ctx).With(slog.String("command", spec.Path))
+
+		cmd := exec.Command(spec.Path, spec.Args...) // #nosec G204 -- commands are configured by the application
+		cmd.Dir = spec.Dir
+		if len(spec.Env) > 0 {
+			cmd.Env = append(os.Environ(), spec.Env...)
+		}
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		stdout := &lineLogger{logger: logger, stream: "stdout"}
+		stderr := &lineLogger{logger: logger, stream: "stderr"}
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		// Don't wait forever for output of orphaned children holding the pipes
+		cmd.WaitDelay = spec.GracePeriod
+
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to start command %q: %w", spec.Path, err)
+		}
+		pgid := cmd.Process.Pid
+		logger.Info("command started", slog.Int("pid", pgid))
+
+		exited := make(chan error, 1)
+		go func() {
+			exited <- cmd.Wait()
+		}()
+
+		var err error
+		select {
+		case err = <-exited:
+			// Kill children left behind in the process group
+			_ = syscall.Kill(-pgid, syscall.SIGKILL)
+		case <-ctx.Done():
+			err = s.stopCommand(logger, pgid, s.commandGracePeriod(spec.GracePeriod), exited)
+		}
+
+		stdout.flush()
+		stderr.flush()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("command %q exited with code %d: %w", spec.Path, exitErr.ExitCode(), err)
+		}
+		if err != nil {
+			return fmt.Errorf("command %q failed: %w", spec.Path, err)
+		}
+
+		logger.Info("command exited")
+		return nil
+	}
+}
+
+// commandGracePeriod caps gracePeriod during shutdown by the time left before the shutdown timeout,
+// keeping a tenth of the timeout like drain does, so the command is killed before it counts as stuck.
+func (s *Supervisor) commandGracePeriod(gracePeriod time.Duration) time.Duration {
+	s.lock.Lock()
+	deadline := s.shutdownBy
+	s.lock.Unlock()
+
+	if deadline.IsZero() {
+		return gracePeriod
+	}
+
+	left := deadline.Sub(s.clock.Now()) - s.shutdownTimeout/10
+	return max(min(gracePeriod, left), 0)
+}
+
+// stopCommand sends SIGTERM to the process group and SIGKILL after the grace period.
+func (s *Supervisor) stopCommand(logger *slog.Logger, pgid int, gracePeriod time.Duration,
+	exited <-chan error,
+) error {
+	logger.Info("stopping command", slog.Int("pid", pgid), slog.Duration("grace_period", gracePeriod))
+	if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+		logger.Error("failed to send SIGTERM to command", slog.String("error", err.Error()))
+	}
+
+	select {
+	case err := <-exited:
+		_ = syscall.Kill(-pgid, syscall.SIGKILL)
+		return err
+	case <-s.clock.After(gracePeriod):
+	}
+
+	logger.Warn("command did not stop within grace period, killing it", slog.Int("pid", pgid))
+	if err := syscall.Kill(-pgid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		logger.Error("failed to send SIGKILL to command", slog.String("error", err.Error()))
+	}
+
+	return <-exited
+}
+
+// lineLogger is an io.Writer which logs every complete line written to it.
+type lineLogger struct {
+	logger *slog.Logger
+	stream string
+	buf    []byte
+}
+
+func (l *lineLogger) Write(p []byte) (int, error) {
+	l.buf = append(l.buf, p...)
+
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		l.log(l.buf[:i])
+		l.buf = l.buf[i+1:]
+	}
+
+	if len(l.buf) >= maxCommandLineLength {
+		l.flush()
+	}
+
+	return len(p), nil
+}
+
+// flush logs a trailing line without newline.
+func (l *lineLogger) flush() {
+	if len(l.buf) > 0 {
+		l.log(l.buf)
+		l.buf = nil
+	}
+}
+
+func (l *lineLogger) log(line []byte) {
+	l.logger.Info(string(bytes.TrimRight(line, "\r")), slog.String("stream", l.stream))
+}
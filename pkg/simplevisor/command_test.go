@@ -0,0 +1,147 @@
+//go:build unix
+
+package simplevisor
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_RegisterCommandLogsOutput(t *testing.T) {
+	logs := &syncBuffer{}
+	s := New(time.Second, slog.New(slog.NewJSONHandler(logs, nil)))
+
+	s.RegisterCommand("echo", CommandSpec{
+		Path: "/bin/sh",
+		Args: []string{"-c", "echo hello; echo oops >&2; printf partial"},
+	})
+	s.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && len(logs.records(t, "command exited")) == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	s.Shutdown()
+
+	for msg, stream := range map[string]string{"hello": "stdout", "oops": "stderr", "partial": "stdout"} {
+		records := logs.records(t, msg)
+		if len(records) != 1 {
+			t.Fatalf("Expected one %q log line, got %d", msg, len(records))
+		}
+		if records[0]["stream"] != stream || records[0]["process_name"] != "echo" {
+			t.Errorf("Unexpected attributes for %q: %v", msg, records[0])
+		}
+	}
+}
+
+func TestSupervisor_RegisterCommandExitCode(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	s.RegisterCommand("failing", CommandSpec{
+		Path: "/bin/sh",
+		Args: []string{"-c", "exit 3"},
+	}, WithRestart(RestartOnFailure, 2, 10*time.Millisecond))
+	s.Run()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type != EventStopped {
+				continue
+			}
+			if event.Err == nil || !strings.Contains(event.Err.Error(), "exited with code 3") {
+				t.Fatalf("Expected exit code error, got %v", event.Err)
+			}
+			waitForStatus(t, s, "failing", StatusStopped, time.Second)
+			s.Shutdown()
+			return
+		case <-timeout:
+			t.Fatal("Command did not exit")
+		}
+	}
+}
+
+func TestSupervisor_RegisterCommandStopsProcessGroup(t *testing.T) {
+	s := createTestSupervisor(5 * time.Second)
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+
+	// The shell and its child ignore SIGTERM, so both need the SIGKILL sent to the process group
+	s.RegisterCommand("sidecar", CommandSpec{
+		Path:        "/bin/sh",
+		Args:        []string{"-c", "trap '' TERM; sleep 30 & echo $! > " + pidFile + "; wait; sleep 30"},
+		GracePeriod: 100 * time.Millisecond,
+	})
+	s.Run()
+
+	var childPid int
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && childPid == 0 {
+		if data, err := os.ReadFile(pidFile); err == nil {
+			childPid, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if childPid == 0 {
+		t.Fatal("Command did not start its child")
+	}
+
+	start := time.Now()
+	s.Shutdown()
+
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("Command should be killed after the grace period, took %v", time.Since(start))
+	}
+
+	if processAlive(childPid) {
+		t.Error("Child of the command should be stopped with its process group")
+	}
+}
+
+func TestSupervisor_RegisterCommandKilledWithinShutdownTimeout(t *testing.T) {
+	logs := &syncBuffer{}
+	s := New(500*time.Millisecond, slog.New(slog.NewJSONHandler(logs, nil)))
+
+	// The default grace period outlasts the shutdown timeout
+	s.RegisterCommand("sidecar", CommandSpec{
+		Path: "/bin/sh",
+		Args: []string{"-c", "trap '' TERM; while :; do sleep 1; done"},
+	})
+	s.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && len(logs.records(t, "command started")) == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	start := time.Now()
+	s.Shutdown()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Command should be killed within the shutdown timeout, took %v", elapsed)
+	}
+	if len(logs.records(t, "command did not stop within grace period, killing it")) != 1 {
+		t.Error("Expected the command to be killed")
+	}
+	if len(logs.records(t, "shutdown timeout exceeded, some processes are still running")) != 0 {
+		t.Error("Command should not be reported stuck")
+	}
+}
+
+// processAlive reports whether pid is running; zombies waiting to be reaped don't count.
+func processAlive(pid int) bool {
+	if stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat")); err == nil {
+		fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+		return len(fields) > 0 && fields[0] != "Z"
+	}
+
+	return syscall.Kill(pid, 0) == nil
+}
@@ -0,0 +1,86 @@
+package simplevisor
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config holds the settings of a supervisor and its processes, usually loaded with pkg/config.
+//
+//	shutdown_timeout: 10s
+//	processes:
+//	  consumer:
+//	    restart: on_failure
+//	    max_restarts: 5
+//	    delay: 2s
+type Config struct {
+	ShutdownTimeout time.Duration            `mapstructure:"shutdown_timeout"`
+	Processes       map[string]ProcessConfig `mapstructure:"processes"`
+}
+
+// ProcessConfig holds the restart settings of a process or pool, keyed by its name in Config.
+// Unset fields keep the value given in code.
+type ProcessConfig struct {
+	Restart     *RestartPolicy `mapstructure:"restart"`
+	MaxRestarts *int           `mapstructure:"max_restarts"`
+	Delay       *time.Duration `mapstructure:"delay"`
+}
+
+// NewFromConfig returns a new Supervisor configured by cfg.
+// Process settings are applied on Register by name and override the options given in code.
+// Start fails and Run starts nothing if there are settings for unknown processes, see CheckConfig.
+func NewFromConfig(cfg Config, sLog *slog.Logger, options ...SupervisorOption) *Supervisor {
+	s := New(cfg.ShutdownTimeout, sLog, options...)
+	s.processConfigs = cfg.Processes
+
+	return s
+}
+
+// CheckConfig returns an error if the config has settings for names which aren't registered
+// as a process or pool. Start and Run call it, call it earlier to fail before other startup work.
+func (s *Supervisor) CheckConfig() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var unknown []string
+	for name := range s.processConfigs {
+		_, isProcess := s.processes[name]
+		_, isPool := s.pools[name]
+		if !isProcess && !isPool {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("config has settings for unknown processes: %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// applyConfig overrides the settings of a process with the config of name, if any.
+func (s *Supervisor) applyConfig(process *Process, name string) {
+	cfg, ok := s.processConfigs[name]
+	if !ok {
+		return
+	}
+
+	if cfg.Restart != nil {
+		process.restartPolicy = *cfg.Restart
+	}
+
+	if cfg.MaxRestarts != nil {
+		process.maxRestarts = *cfg.MaxRestarts
+	}
+
+	if cfg.Delay != nil {
+		process.restartDelay = *cfg.Delay
+		if process.restartDelay <= 0 {
+			process.restartDelay = DefaultRestartDelay
+		}
+	}
+}
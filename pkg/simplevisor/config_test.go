@@ -0,0 +1,126 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	onFailure := RestartOnFailure
+	maxRestarts := 7
+	delay := 50 * time.Millisecond
+
+	s := NewFromConfig(Config{
+		ShutdownTimeout: 2 * time.Second,
+		Processes: map[string]ProcessConfig{
+			"worker": {Restart: &onFailure, MaxRestarts: &maxRestarts, Delay: &delay},
+			"pool":   {MaxRestarts: &maxRestarts},
+		},
+	}, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})))
+
+	if s.shutdownTimeout != 2*time.Second {
+		t.Errorf("Expected shutdown timeout 2s, got %v", s.shutdownTimeout)
+	}
+
+	handler := func(ctx context.Context) error { return nil }
+	s.Register("worker", handler, WithRestart(RestartAlways, 1, time.Second))
+	s.Register("other", handler, WithRestart(RestartAlways, 1, time.Second))
+	s.RegisterPool("pool", 2, handler, WithRestart(RestartAlways, 1, 20*time.Millisecond))
+
+	worker := s.processes["worker"]
+	if worker.restartPolicy != RestartOnFailure || worker.maxRestarts != 7 || worker.restartDelay != delay {
+		t.Errorf("Expected config to override worker options, got policy=%v max=%d delay=%v",
+			worker.restartPolicy, worker.maxRestarts, worker.restartDelay)
+	}
+
+	other := s.processes["other"]
+	if other.restartPolicy != RestartAlways || other.maxRestarts != 1 {
+		t.Errorf("Expected process without config to keep its options, got policy=%v max=%d",
+			other.restartPolicy, other.maxRestarts)
+	}
+
+	replica := s.processes[replicaName("pool", 0)]
+	if replica.maxRestarts != 7 || replica.restartPolicy != RestartAlways || replica.restartDelay != 20*time.Millisecond {
+		t.Errorf("Expected pool config to override only max restarts, got policy=%v max=%d delay=%v",
+			replica.restartPolicy, replica.maxRestarts, replica.restartDelay)
+	}
+
+	if err := s.CheckConfig(); err != nil {
+		t.Errorf("Expected no config error, got %v", err)
+	}
+}
+
+func TestSupervisor_CheckConfig(t *testing.T) {
+	s := NewFromConfig(Config{
+		Processes: map[string]ProcessConfig{
+			"wroker":  {},
+			"missing": {},
+			"worker":  {},
+		},
+	}, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})))
+
+	s.Register("worker", func(ctx context.Context) error { return nil })
+
+	err := s.CheckConfig()
+	if err == nil {
+		t.Fatal("Expected error for unknown processes")
+	}
+	if !strings.Contains(err.Error(), "missing, wroker") {
+		t.Errorf("Expected sorted unknown names in error, got %v", err)
+	}
+}
+
+func TestSupervisor_StartFailsOnUnknownProcessConfig(t *testing.T) {
+	s := NewFromConfig(Config{
+		Processes: map[string]ProcessConfig{
+			"wroker": {},
+		},
+	}, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})))
+	defer s.Shutdown()
+
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	err := s.Start()
+	if err == nil || !strings.Contains(err.Error(), "wroker") {
+		t.Fatalf("Expected error for unknown process, got %v", err)
+	}
+
+	s.lock.Lock()
+	running, done := s.running, s.processes["worker"].done
+	s.lock.Unlock()
+	if running || done != nil {
+		t.Error("Expected no process to be started")
+	}
+}
+
+func TestSupervisor_RunLogsUnknownProcessConfig(t *testing.T) {
+	logs := &syncBuffer{}
+	s := NewFromConfig(Config{
+		Processes: map[string]ProcessConfig{
+			"wroker": {},
+		},
+	}, slog.New(slog.NewJSONHandler(logs, nil)))
+	defer s.Shutdown()
+
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+
+	records := logs.records(t, "failed to run supervisor")
+	if len(records) != 1 || records[0]["level"] != "ERROR" {
+		t.Fatalf("Expected an error log for the unknown process, got %v", records)
+	}
+
+	if s.IsRunning("worker") {
+		t.Error("Expected no process to be started")
+	}
+}
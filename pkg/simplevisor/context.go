@@ -0,0 +1,80 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// contextKey is the type of keys for values the supervisor puts into process contexts.
+type contextKey int
+
+const (
+	replicaIndexKey contextKey = iota
+	runInfoKey
+)
+
+// runInfo describes a single run of a process.
+type runInfo struct {
+	name      string
+	attempt   int
+	startedAt time.Time
+	logger    *slog.Logger
+	draining  <-chan struct{}
+
+	restartPolicy  RestartPolicy
+	recoverHandler RecoverFunc
+	labels         map[string]string // See WithLabels
+	ready          func()            // Nil outside a supervised process run
+	cleanups       *cleanupStack     // See Cleanup
+	children       *childGroup       // See Spawn
+}
+
+func runInfoFrom(ctx context.Context) (runInfo, bool) {
+	run, ok := ctx.Value(runInfoKey).(runInfo)
+	return run, ok
+}
+
+// ReplicaIndex returns the replica index of a pool process from its context.
+// The second return value is false if the process isn't a pool replica.
+func ReplicaIndex(ctx context.Context) (int, bool) {
+	index, ok := ctx.Value(replicaIndexKey).(int)
+	return index, ok
+}
+
+// Logger returns the logger of the process run, tagged with process_name and attempt.
+// Outside a supervised process it returns slog.Default().
+func Logger(ctx context.Context) *slog.Logger {
+	if run, ok := runInfoFrom(ctx); ok && run.logger != nil {
+		return run.logger
+	}
+
+	return slog.Default()
+}
+
+// ProcessName returns the name of the process from its context, or an empty string outside a process.
+func ProcessName(ctx context.Context) string {
+	run, _ := runInfoFrom(ctx)
+	return run.name
+}
+
+// Attempt returns the 1-based number of the current run of the process, or 0 outside a process.
+func Attempt(ctx context.Context) int {
+	run, _ := runInfoFrom(ctx)
+	return run.attempt
+}
+
+// StartedAt returns the start time of the current run of the process, or the zero time outside a process.
+func StartedAt(ctx context.Context) time.Time {
+	run, _ := runInfoFrom(ctx)
+	return run.startedAt
+}
+
+// Draining returns a channel which is closed when the supervisor starts to shut down.
+// A process should stop taking new work once it is closed, finish its in-flight work and return;
+// its context is cancelled only after the drain period, see WithDrainPeriod.
+// Outside a process it returns nil, which blocks forever in a select.
+func Draining(ctx context.Context) <-chan struct{} {
+	run, _ := runInfoFrom(ctx)
+	return run.draining
+}
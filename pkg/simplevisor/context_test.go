@@ -0,0 +1,94 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_ProcessContext(t *testing.T) {
+	logs := &syncBuffer{}
+	s := New(time.Second, slog.New(slog.NewJSONHandler(logs, nil)))
+
+	var lock sync.Mutex
+	var attempts []int
+	var names []string
+	var startedAt []time.Time
+
+	handler := func(ctx context.Context) error {
+		lock.Lock()
+		attempts = append(attempts, Attempt(ctx))
+		names = append(names, ProcessName(ctx))
+		startedAt = append(startedAt, StartedAt(ctx))
+		lock.Unlock()
+
+		Logger(ctx).Info("hello from process")
+
+		if Attempt(ctx) == 1 {
+			return errors.New("first attempt fails")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	s.Register("context-test", handler, WithRestart(RestartOnFailure, 3, 10*time.Millisecond))
+	s.Run()
+
+	time.Sleep(50 * time.Millisecond)
+	waitForStatus(t, s, "context-test", StatusRunning, time.Second)
+	s.Shutdown()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("Expected attempts [1 2], got %v", attempts)
+	}
+
+	for i, name := range names {
+		if name != "context-test" {
+			t.Errorf("Expected process name context-test, got %q", name)
+		}
+		if startedAt[i].IsZero() {
+			t.Error("StartedAt should be set")
+		}
+	}
+
+	records := logs.records(t, "hello from process")
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 log records, got %d", len(records))
+	}
+
+	for i, record := range records {
+		if record["process_name"] != "context-test" {
+			t.Errorf("Expected process_name attribute, got %v", record["process_name"])
+		}
+		if record["attempt"] != float64(i+1) {
+			t.Errorf("Expected attempt %d, got %v", i+1, record["attempt"])
+		}
+	}
+
+	info, _ := s.GetProcessInfo("context-test")
+	if info.Attempts != 2 {
+		t.Errorf("Expected 2 attempts in process info, got %d", info.Attempts)
+	}
+}
+
+func TestContextAccessorsOutsideProcess(t *testing.T) {
+	ctx := context.Background()
+
+	if Logger(ctx) != slog.Default() {
+		t.Error("Logger should fall back to slog.Default()")
+	}
+
+	if ProcessName(ctx) != "" || Attempt(ctx) != 0 || !StartedAt(ctx).IsZero() {
+		t.Error("Accessors should return zero values outside a process")
+	}
+
+	if _, ok := ReplicaIndex(ctx); ok {
+		t.Error("ReplicaIndex should report false outside a pool")
+	}
+}
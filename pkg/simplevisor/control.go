@@ -0,0 +1,406 @@
+package simplevisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// controlTimeout bounds reading a control request and writing each response
+const controlTimeout = 10 * time.Second
+
+// Commands of the control protocol, see ServeControl
+const (
+	ControlStatus  = "status"
+	ControlRestart = "restart"
+	ControlStop    = "stop"
+	ControlPause   = "pause"
+	ControlResume  = "resume"
+	ControlTail    = "tail"
+)
+
+// ControlRequest is a request to the control socket, sent as a single JSON line.
+type ControlRequest struct {
+	Command string `json:"command"`
+	Name    string `json:"name,omitempty"`
+}
+
+// ControlResponse is a JSON line sent back by the control socket.
+// A tail request is answered with a response per event until the connection is closed.
+type ControlResponse struct {
+	Processes []ControlProcess `json:"processes,omitempty"`
+	Event     *ControlEvent    `json:"event,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// ControlProcess is a process in the status of the control socket.
+type ControlProcess struct {
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	RestartCount int               `json:"restart_count"`
+	Attempts     int               `json:"attempts"`
+	StartedAt    time.Time         `json:"started_at"`
+	Ready        bool              `json:"ready"`
+	Breaker      string            `json:"breaker"`
+	Pool         string            `json:"pool,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	LastError    string            `json:"last_error,omitempty"`
+	Goroutines   int               `json:"goroutines"` // Live goroutines of the process, see GoroutineCounts
+	Children     int               `json:"children"`   // Live child goroutines, see Spawn
+}
+
+// ControlEvent is a supervisor event streamed by the control socket.
+type ControlEvent struct {
+	Type         string    `json:"type"`
+	Process      string    `json:"process,omitempty"`
+	Time         time.Time `json:"time"`
+	Error        string    `json:"error,omitempty"`
+	RestartCount int       `json:"restart_count,omitempty"`
+}
+
+// WithControlSocket serves the control protocol on a Unix socket at path from Run until shutdown,
+// see ServeControl. The socket is only accessible by the owner of the process.
+func WithControlSocket(path string) SupervisorOption {
+	return func(s *Supervisor) {
+		s.controlSocket = path
+	}
+}
+
+// ServeControl serves the control protocol on l until ctx is done.
+// It exposes the process table and the Restart, Stop, Pause and Resume actions, and streams events,
+// like supervisorctl. Use ControlClient to talk to it.
+func (s *Supervisor) ServeControl(ctx context.Context, l net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer l.Close()
+
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept control connection: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+
+			// Unblock a handler waiting on its client
+			stop := context.AfterFunc(ctx, func() {
+				_ = conn.Close()
+			})
+			defer stop()
+
+			s.handleControl(ctx, conn)
+		}()
+	}
+}
+
+// listenControl starts serving the control socket of WithControlSocket.
+func (s *Supervisor) listenControl() {
+	l, err := listenUnix(s.controlSocket)
+	if err != nil {
+		s.logger.Error("failed to listen on control socket",
+			slog.String("path", s.controlSocket),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	s.logger.Info("serving control socket", slog.String("path", s.controlSocket))
+	go func() {
+		if err := s.ServeControl(s.shutDownCtx, l); err != nil {
+			s.logger.Error("control socket failed", slog.String("error", err.Error()))
+		}
+	}()
+}
+
+// listenUnix listens on a Unix socket at path, replacing a stale socket file.
+// The socket controls the processes, so it is only accessible by the owner: it is created
+// in a private directory, restricted and only then moved to path.
+func listenUnix(path string) (net.Listener, error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("control socket %s is in use", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale control socket: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create control socket directory: %w", err)
+	}
+
+	// MkdirTemp creates the directory with mode 0700
+	private, err := os.MkdirTemp(dir, ".control-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create control socket: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(private) }()
+
+	tmp := filepath.Join(private, "control.sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	ul := l.(*net.UnixListener)
+	ul.SetUnlinkOnClose(false) // The socket is moved, removed by unixListener instead
+
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("failed to restrict control socket: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("failed to create control socket: %w", err)
+	}
+
+	return &unixListener{UnixListener: ul, path: path}, nil
+}
+
+// unixListener removes its socket file on Close.
+type unixListener struct {
+	*net.UnixListener
+	path string
+	once sync.Once
+}
+
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	l.once.Do(func() {
+		_ = os.Remove(l.path)
+	})
+
+	return err
+}
+
+func (s *Supervisor) handleControl(ctx context.Context, conn net.Conn) {
+	encoder := &controlEncoder{conn: conn, encoder: json.NewEncoder(conn)}
+
+	var req ControlRequest
+	_ = conn.SetReadDeadline(time.Now().Add(controlTimeout))
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		_ = encoder.Encode(ControlResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	// A tail connection reads until the client disconnects
+	_ = conn.SetReadDeadline(time.Time{})
+
+	s.logger.Info("control request", slog.String("command", req.Command), slog.String("process_name", req.Name))
+
+	var err error
+	switch req.Command {
+	case ControlStatus:
+		_ = encoder.Encode(ControlResponse{Processes: s.controlProcesses()})
+		return
+	case ControlTail:
+		s.tailControl(ctx, conn, encoder)
+		return
+	case ControlRestart:
+		err = s.Restart(req.Name)
+	case ControlStop:
+		err = s.Stop(req.Name)
+	case ControlPause:
+		err = s.Pause(req.Name)
+	case ControlResume:
+		err = s.Resume(req.Name)
+	default:
+		err = fmt.Errorf("unknown command %q", req.Command)
+	}
+
+	var resp ControlResponse
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	_ = encoder.Encode(resp)
+}
+
+// controlEncoder writes responses to a control connection, each within controlTimeout.
+type controlEncoder struct {
+	conn    net.Conn
+	encoder *json.Encoder
+}
+
+func (e *controlEncoder) Encode(resp ControlResponse) error {
+	_ = e.conn.SetWriteDeadline(time.Now().Add(controlTimeout))
+	return e.encoder.Encode(resp)
+}
+
+// tailControl streams events to conn until the client disconnects or ctx is done.
+func (s *Supervisor) tailControl(ctx context.Context, conn net.Conn, encoder *controlEncoder) {
+	events, unsubscribe := s.Subscribe(DefaultEventBuffer)
+	defer unsubscribe()
+
+	// The client doesn't send anything else; a read returns once it disconnects
+	closed := make(chan struct{})
+	go func() {
+		_, _ = conn.Read(make([]byte, 1))
+		close(closed)
+	}()
+
+	for {
+		select {
+		case event := <-events:
+			if err := encoder.Encode(ControlResponse{Event: controlEvent(event)}); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Supervisor) controlProcesses() []ControlProcess {
+	infos := s.Processes(nil)
+	goroutines := s.GoroutineCounts()
+
+	processes := make([]ControlProcess, 0, len(infos))
+	for _, info := range infos {
+		process := ControlProcess{
+			Name:         info.Name,
+			Status:       info.Status.String(),
+			RestartCount: info.RestartCount,
+			Attempts:     info.Attempts,
+			StartedAt:    info.StartedAt,
+			Ready:        info.Ready,
+			Breaker:      info.Breaker.String(),
+			Pool:         info.Pool,
+			Labels:       info.Labels,
+			Goroutines:   goroutines[info.Name],
+			Children:     info.Children,
+		}
+		if n := len(info.Exits); n > 0 && info.Exits[n-1].Err != nil {
+			process.LastError = info.Exits[n-1].Err.Error()
+		}
+
+		processes = append(processes, process)
+	}
+
+	return processes
+}
+
+func controlEvent(event Event) *ControlEvent {
+	e := &ControlEvent{
+		Type:         event.Type.String(),
+		Process:      event.Process,
+		Time:         event.Time,
+		RestartCount: event.RestartCount,
+	}
+	if event.Err != nil {
+		e.Error = event.Err.Error()
+	}
+
+	return e
+}
+
+// ControlClient talks to the control socket of a supervisor, see WithControlSocket.
+type ControlClient struct {
+	path string
+}
+
+// NewControlClient returns a client of the control socket at path.
+func NewControlClient(path string) *ControlClient {
+	return &ControlClient{path: path}
+}
+
+// Status returns the processes of the supervisor, sorted by name.
+func (c *ControlClient) Status(ctx context.Context) ([]ControlProcess, error) {
+	resp, err := c.roundTrip(ctx, ControlRequest{Command: ControlStatus})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Processes, nil
+}
+
+// Do runs an action on a process: ControlRestart, ControlStop, ControlPause or ControlResume.
+func (c *ControlClient) Do(ctx context.Context, command string, name string) error {
+	_, err := c.roundTrip(ctx, ControlRequest{Command: command, Name: name})
+	return err
+}
+
+// Tail calls fn with every supervisor event until ctx is done or the supervisor shuts down.
+func (c *ControlClient) Tail(ctx context.Context, fn func(ControlEvent)) error {
+	conn, err := c.send(ctx, ControlRequest{Command: ControlTail})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var resp ControlResponse
+		if err := decoder.Decode(&resp); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("control socket closed: %w", err)
+		}
+
+		if resp.Error != "" {
+			return errors.New(resp.Error)
+		}
+		if resp.Event != nil {
+			fn(*resp.Event)
+		}
+	}
+}
+
+func (c *ControlClient) roundTrip(ctx context.Context, req ControlRequest) (ControlResponse, error) {
+	conn, err := c.send(ctx, req)
+	if err != nil {
+		return ControlResponse{}, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	var resp ControlResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return ControlResponse{}, fmt.Errorf("failed to read control response: %w", err)
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+
+	return resp, nil
+}
+
+func (c *ControlClient) send(ctx context.Context, req ControlRequest) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to control socket %s: %w", c.path, err)
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to send control request: %w", err)
+	}
+
+	return conn, nil
+}
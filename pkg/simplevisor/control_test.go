@@ -0,0 +1,297 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startControl serves the control socket of s in a temporary directory and returns a client.
+func startControl(t *testing.T, s *Supervisor) *ControlClient {
+	t.Helper()
+
+	// Unix socket paths are limited in length, t.TempDir() can be too long
+	dir, err := os.MkdirTemp("", "simplevisor")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "ctl.sock")
+	l, err := listenUnix(path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- s.ServeControl(ctx, l)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-served; err != nil {
+			t.Errorf("ServeControl failed: %v", err)
+		}
+	})
+
+	return NewControlClient(path)
+}
+
+func TestControl_StatusAndActions(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	s.Register("consumer", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithLabels(map[string]string{"broker": "rabbit"}))
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "consumer", StatusRunning, time.Second)
+	client := startControl(t, s)
+	ctx := context.Background()
+
+	processes, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(processes) != 1 || processes[0].Name != "consumer" || processes[0].Status != "running" {
+		t.Fatalf("Unexpected status: %+v", processes)
+	}
+	if processes[0].Labels["broker"] != "rabbit" {
+		t.Errorf("Expected labels in status, got %v", processes[0].Labels)
+	}
+
+	if err := client.Do(ctx, ControlPause, "consumer"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if status, _ := s.GetProcessStatus("consumer"); status != StatusPaused {
+		t.Errorf("Expected paused process, got %v", status)
+	}
+
+	if err := client.Do(ctx, ControlResume, "consumer"); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	waitForStatus(t, s, "consumer", StatusRunning, time.Second)
+
+	if err := client.Do(ctx, ControlStop, "consumer"); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if status, _ := s.GetProcessStatus("consumer"); status != StatusStopped {
+		t.Errorf("Expected stopped process, got %v", status)
+	}
+
+	if err := client.Do(ctx, ControlRestart, "consumer"); err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+	waitForStatus(t, s, "consumer", StatusRunning, time.Second)
+
+	if err := client.Do(ctx, ControlStop, "missing"); err == nil {
+		t.Error("Expected error for an unknown process")
+	}
+	if err := client.Do(ctx, "explode", "consumer"); err == nil {
+		t.Error("Expected error for an unknown command")
+	}
+}
+
+func TestControl_Tail(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "worker", StatusRunning, time.Second)
+	client := startControl(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan ControlEvent, 16)
+	tailed := make(chan error, 1)
+	go func() {
+		tailed <- client.Tail(ctx, func(e ControlEvent) { events <- e })
+	}()
+
+	// Wait until the tail subscribed before causing events
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.events.lock.Lock()
+		subscribed := len(s.events.subscribers) > 0
+		s.events.lock.Unlock()
+		if subscribed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Tail did not subscribe")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := s.Pause("worker"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case e := <-events:
+			if e.Type == "paused" && e.Process == "worker" {
+				cancel()
+				if err := <-tailed; err != nil {
+					t.Errorf("Tail failed: %v", err)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("Expected a paused event")
+		}
+	}
+}
+
+func TestListenUnix_SocketInUse(t *testing.T) {
+	dir, err := os.MkdirTemp("", "simplevisor")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ctl.sock")
+	l, err := listenUnix(path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	if _, err := listenUnix(path); err == nil {
+		t.Error("Expected error for a socket in use")
+	}
+
+	// A stale socket file is replaced
+	_ = l.(*unixListener).UnixListener.Close()
+	l, err = listenUnix(path)
+	if err != nil {
+		t.Fatalf("Expected stale socket to be replaced, got %v", err)
+	}
+	_ = l.Close()
+}
+
+func TestListenUnix_PrivateSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "simplevisor")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A missing directory is created for the owner only
+	path := filepath.Join(dir, "run", "ctl.sock")
+	l, err := listenUnix(path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Expected socket directory, got %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("Expected directory permissions 0700, got %v", perm)
+	}
+
+	// The private directory the socket is created in is removed
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Failed to read socket directory: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "ctl.sock" {
+		t.Errorf("Expected only the socket in its directory, got %v", entries)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Expected socket to accept connections, got %v", err)
+	}
+	_ = conn.Close()
+
+	_ = l.Close()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected socket to be removed on close, got %v", err)
+	}
+}
+
+func TestControl_WithControlSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "simplevisor")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ctl.sock")
+	s := New(time.Second, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithControlSocket(path))
+	s.Run()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected control socket, got %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("Expected socket permissions 0600, got %v", perm)
+	}
+
+	if _, err := NewControlClient(path).Status(context.Background()); err != nil {
+		t.Errorf("Status failed: %v", err)
+	}
+
+	s.Shutdown()
+	time.Sleep(20 * time.Millisecond)
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected socket to be removed on shutdown, got %v", err)
+	}
+}
+
+func TestServeControl_SilentClientDoesNotBlockShutdown(t *testing.T) {
+	dir, err := os.MkdirTemp("", "simplevisor")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ctl.sock")
+	l, err := listenUnix(path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	s := createTestSupervisor(time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- s.ServeControl(ctx, l)
+	}()
+
+	// Connect without sending a request
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	// Let the handler block on reading the request
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("ServeControl failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ServeControl did not return while a client was connected")
+	}
+}
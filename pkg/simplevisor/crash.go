@@ -0,0 +1,198 @@
+package simplevisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxCrashReports is the number of crash reports kept if WithCrashReports is given no limit
+	DefaultMaxCrashReports = 20
+
+	crashReportPrefix = "crash-"
+	crashReportSuffix = ".json"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// CrashReport is the content of a crash report file, see WithCrashReports.
+type CrashReport struct {
+	Process      string      `json:"process"`
+	Time         time.Time   `json:"time"`
+	Attempt      int         `json:"attempt"`
+	Panic        string      `json:"panic"`
+	Stack        string      `json:"stack"`
+	Goroutines   string      `json:"goroutines"` // Stacks of all goroutines
+	RestartCount int         `json:"restart_count"`
+	Exits        []CrashExit `json:"exits,omitempty"` // Exit history before the panic, see WithExitHistory
+	Build        any         `json:"build,omitempty"`
+}
+
+// CrashExit is an ExitRecord in a crash report.
+type CrashExit struct {
+	Time         time.Time `json:"time"`
+	Duration     string    `json:"duration"`
+	Error        string    `json:"error,omitempty"`
+	Panic        string    `json:"panic,omitempty"`
+	Restarted    bool      `json:"restarted"`
+	CleanupError string    `json:"cleanup_error,omitempty"`
+}
+
+// crashReports holds the settings of WithCrashReports.
+type crashReports struct {
+	dir        string
+	maxReports int
+	build      any
+}
+
+// WithCrashReports writes a JSON crash report to dir whenever a process or job panics.
+// Only the newest maxReports reports are kept, DefaultMaxCrashReports if maxReports <= 0.
+// build is added to every report as is, e.g. the build info of the application:
+//
+//	simplevisor.WithCrashReports("/var/log/app/crashes", 50, app.GetInfo())
+func WithCrashReports(dir string, maxReports int, build any) SupervisorOption {
+	return func(s *Supervisor) {
+		if maxReports <= 0 {
+			maxReports = DefaultMaxCrashReports
+		}
+
+		s.crashReports = &crashReports{dir: dir, maxReports: maxReports, build: build}
+	}
+}
+
+// reportCrash writes the crash report of a panicked run, if enabled.
+// Failures are logged as a crash report must never take down the supervisor.
+func (s *Supervisor) reportCrash(run runInfo, panicErr *PanicError) {
+	if s.crashReports == nil {
+		return
+	}
+
+	report := CrashReport{
+		Process:    run.name,
+		Time:       s.clock.Now(),
+		Attempt:    run.attempt,
+		Panic:      fmt.Sprint(panicErr.Value),
+		Stack:      string(panicErr.Stack),
+		Goroutines: string(goroutineStacks()),
+		Build:      s.crashReports.build,
+	}
+
+	s.lock.Lock()
+	if process, ok := s.processes[run.name]; ok {
+		report.RestartCount = process.restartCount
+		for _, exit := range process.exits {
+			report.Exits = append(report.Exits, newCrashExit(exit))
+		}
+	}
+	s.lock.Unlock()
+
+	path, err := s.crashReports.write(report)
+	if err != nil {
+		s.logger.Error("failed to write crash report",
+			slog.String("process_name", run.name),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	s.logger.Info("crash report written",
+		slog.String("process_name", run.name),
+		slog.String("path", path))
+
+	if err := s.crashReports.prune(); err != nil {
+		s.logger.Error("failed to remove old crash reports", slog.String("error", err.Error()))
+	}
+}
+
+func newCrashExit(exit ExitRecord) CrashExit {
+	c := CrashExit{
+		Time:      exit.Time,
+		Duration:  exit.Duration.String(),
+		Restarted: exit.Restarted,
+	}
+	if exit.Err != nil {
+		c.Error = exit.Err.Error()
+	}
+	if exit.Panic != nil {
+		c.Panic = fmt.Sprint(exit.Panic)
+	}
+	if exit.CleanupErr != nil {
+		c.CleanupError = exit.CleanupErr.Error()
+	}
+
+	return c
+}
+
+// write writes a report to a new file and returns its path.
+// The file is renamed into place so readers never see a partial report.
+func (c *crashReports) write(report CrashReport) (string, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode crash report: %w", err)
+	}
+
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create crash report directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".crash-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create crash report: %w", err)
+	}
+	// Gone after a successful rename
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+
+	// The timestamp first sorts reports by time
+	name := crashReportPrefix + report.Time.UTC().Format("20060102T150405.000000000Z") + "-" +
+		unsafeFileChars.ReplaceAllString(report.Process, "_") + crashReportSuffix
+	path := filepath.Join(c.dir, name)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+
+	return path, nil
+}
+
+// prune removes the oldest reports beyond maxReports.
+func (c *crashReports) prune() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	var reports []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, crashReportPrefix) &&
+			strings.HasSuffix(name, crashReportSuffix) {
+			reports = append(reports, name)
+		}
+	}
+
+	if len(reports) <= c.maxReports {
+		return nil
+	}
+
+	sort.Strings(reports)
+	for _, name := range reports[:len(reports)-c.maxReports] {
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
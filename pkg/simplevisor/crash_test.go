@@ -0,0 +1,102 @@
+package simplevisor
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func crashReportFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, crashReportPrefix+"*"+crashReportSuffix))
+	if err != nil {
+		t.Fatalf("failed to list crash reports: %v", err)
+	}
+
+	return files
+}
+
+func TestWithCrashReports(t *testing.T) {
+	dir := t.TempDir()
+	build := map[string]string{"version": "1.2.3"}
+	s := New(time.Second, slog.New(slog.DiscardHandler), WithCrashReports(dir, 2, build))
+
+	var runs atomic.Int32
+	s.Register("crashing", func(ctx context.Context) error {
+		if runs.Add(1) <= 4 {
+			panic("boom")
+		}
+		<-ctx.Done()
+		return nil
+	}, WithRestart(RestartOnFailure, 10, 10*time.Millisecond))
+	s.Run()
+	defer s.Shutdown()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runs.Load() < 5 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	files := crashReportFiles(t, dir)
+	if len(files) != 2 {
+		t.Fatalf("expected 2 retained crash reports, got %d: %v", len(files), files)
+	}
+
+	data, err := os.ReadFile(files[len(files)-1])
+	if err != nil {
+		t.Fatalf("failed to read crash report: %v", err)
+	}
+
+	var report CrashReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid crash report: %v", err)
+	}
+
+	if report.Process != "crashing" || report.Panic != "boom" || report.Attempt != 4 {
+		t.Errorf("unexpected report: process %q, panic %q, attempt %d", report.Process, report.Panic, report.Attempt)
+	}
+	if !strings.Contains(report.Stack, "TestWithCrashReports") {
+		t.Errorf("expected stack of the panic, got %q", report.Stack)
+	}
+	if !strings.Contains(report.Goroutines, "goroutine ") {
+		t.Errorf("expected goroutine dump, got %q", report.Goroutines)
+	}
+	if report.RestartCount != 3 || len(report.Exits) != 3 {
+		t.Errorf("expected 3 restarts and exits before the panic, got %d and %d", report.RestartCount, len(report.Exits))
+	}
+	if len(report.Exits) > 0 && (report.Exits[0].Panic != "boom" || !report.Exits[0].Restarted) {
+		t.Errorf("unexpected exit: %+v", report.Exits[0])
+	}
+	if got, _ := report.Build.(map[string]any); got["version"] != "1.2.3" {
+		t.Errorf("expected build info, got %v", report.Build)
+	}
+}
+
+func TestWithCrashReports_JobAndFileName(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crashes")
+	s := New(time.Second, slog.New(slog.DiscardHandler), WithCrashReports(dir, 0, nil))
+	defer s.Shutdown()
+
+	job := s.Submit("jobs/import", func(ctx context.Context) error {
+		panic("bad row")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = job.Wait(ctx)
+
+	files := crashReportFiles(t, dir)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 crash report, got %d", len(files))
+	}
+	if !strings.HasSuffix(files[0], "-jobs_import.json") {
+		t.Errorf("expected process name in file name, got %s", filepath.Base(files[0]))
+	}
+}
@@ -24,7 +24,9 @@
 //	})
 //
 //	// Start all processes
-//	supervisor.Run()
+//	if err := supervisor.Start(); err != nil {
+//		log.Fatal(err)
+//	}
 //
 //	// Wait for shutdown signal and cleanup
 //	supervisor.WaitOnShutdownSignal(func() {
@@ -47,6 +49,76 @@
 //	supervisor.Register("resilient", handler,
 //		simplevisor.WithRestart(simplevisor.RestartOnFailure, 3, 1*time.Second))
 //
+// # Circuit Breaker
+//
+// By default a process which hits its restart limit is stopped for good.
+// A circuit breaker gives it a middle ground to survive longer outages:
+//
+//	supervisor.Register("outbox-relay", relay,
+//		simplevisor.WithRestart(simplevisor.RestartOnFailure, 5, time.Second),
+//		simplevisor.WithCircuitBreaker(2*time.Minute, 5))
+//
+// When the restart limit trips, the breaker opens and the process cools off.
+// After the cool-off a single half-open run is allowed. If it stays up for the
+// healthy duration the breaker closes and the restart budget is restored,
+// otherwise the breaker opens again. After the given number of failed
+// half-open runs the process is stopped. The breaker state is available via
+// GetProcessInfo().
+//
+// # Leader Election
+//
+// Processes such as schedulers and outbox relays can be restricted to a single
+// replica. A leader-elected process only runs while it holds the lease of its
+// Locker and is cancelled as soon as the lease is lost:
+//
+//	locker := simplevisor.NewFileLocker("/var/run/app/scheduler.lock", time.Second)
+//	supervisor.Register("scheduler", scheduler,
+//		simplevisor.WithRestart(simplevisor.RestartAlways, 3, time.Second),
+//		simplevisor.WithLeaderElection(locker))
+//
+// While waiting for the lease the process is in StatusStandby. Losing the lease
+// doesn't count against the restart budget. FileLocker uses flock(2) and
+// MemoryLocker is meant for tests; other backends implement the Locker interface.
+//
+// # External Commands
+//
+// Sidecar binaries can be supervised like any other process (unix only):
+//
+//	supervisor.RegisterCommand("exporter", simplevisor.CommandSpec{
+//		Path:        "/usr/local/bin/exporter",
+//		Args:        []string{"--port", "9100"},
+//		GracePeriod: 10 * time.Second,
+//	}, simplevisor.WithRestart(simplevisor.RestartOnFailure, 5, time.Second))
+//
+// The command runs in its own process group and its stdout and stderr are
+// logged line by line. On stop the group receives SIGTERM and, after the grace
+// period, SIGKILL. A non-zero exit code is a failure under the restart policy.
+// On shutdown commands only receive SIGTERM once the drain period is over, and
+// the grace period is cut short to kill them within the shutdown timeout.
+//
+// # Run-Time Limits
+//
+// WithMaxRuntime recycles a process periodically, e.g. a worker which leaks
+// memory. The context of every run is cancelled after the max runtime and the
+// process is restarted without using up its restart budget:
+//
+//	supervisor.Register("worker", worker,
+//		simplevisor.WithRestart(simplevisor.RestartAlways, 3, time.Second),
+//		simplevisor.WithMaxRuntime(6*time.Hour))
+//
+// WithStartupTimeout cancels a run which doesn't signal readiness in time; the
+// run fails with ErrStartupTimeout and is handled under its restart policy:
+//
+//	supervisor.Register("server", func(ctx context.Context) error {
+//		if err := connect(ctx); err != nil {
+//			return err
+//		}
+//		simplevisor.Ready(ctx)
+//		return serve(ctx)
+//	}, simplevisor.WithStartupTimeout(10*time.Second))
+//
+// ProcessInfo.Ready reports whether the current run signalled readiness.
+//
 // # Panic Recovery
 //
 // Handle panics in processes with custom recovery logic:
@@ -57,6 +129,164 @@
 //			// Send alert, record metrics, etc.
 //		}))
 //
+// WithCrashReports writes a JSON crash report for every panic of a process or
+// job. A report holds the panic value and stack, a dump of all goroutines, the
+// restart history of the process and the given build info. Only the newest
+// reports are kept:
+//
+//	supervisor := simplevisor.New(5*time.Second, logger,
+//		simplevisor.WithCrashReports("/var/log/gohabit/crashes", 50, app.GetInfo()))
+//
+// # Middleware
+//
+// Middlewares wrap every run of a process with cross-cutting behaviour such as
+// timing, tracing or feature-flag gates. The first middleware is the outermost one:
+//
+//	timing := func(next simplevisor.ProcessFunc) simplevisor.ProcessFunc {
+//		return func(ctx context.Context) error {
+//			start := time.Now()
+//			err := next(ctx)
+//			simplevisor.Logger(ctx).Info("run finished", "duration", time.Since(start))
+//			return err
+//		}
+//	}
+//
+//	// Around every process and job
+//	supervisor.Use(timing)
+//
+//	// Around a single process, inside the supervisor middlewares
+//	supervisor.Register("worker", worker, simplevisor.WithMiddleware(gate))
+//
+// The supervisor chain starts with the built-in RecoverMiddleware,
+// LoggingMiddleware and MetricsMiddleware. SetMiddleware replaces the whole
+// chain to reorder or drop them:
+//
+//	supervisor.SetMiddleware(tracing, supervisor.RecoverMiddleware(), supervisor.MetricsMiddleware())
+//
+// A panic which isn't recovered by the chain is still recovered by the
+// supervisor and handled as a failed run.
+//
+// # Jobs
+//
+// One-shot work such as migrations and backfills can be submitted as a job.
+// A job runs once under supervision, is retried on failure according to its
+// restart policy and keeps its result:
+//
+//	job := supervisor.Submit("migrate", migrate,
+//		simplevisor.WithRestart(simplevisor.RestartOnFailure, 3, time.Second))
+//
+//	if err := job.Wait(ctx); err != nil {
+//		log.Printf("migration failed after %d attempts: %v", job.Attempts(), err)
+//	}
+//
+// Jobs can be submitted before or after Run(). Graceful shutdown waits for
+// in-flight jobs within the shutdown timeout; jobs submitted once shutdown has
+// started fail with ErrDraining. WithMaxRuntime and WithStartupTimeout bound
+// every attempt of a job, and a job can't use the name of a process.
+//
+// Go runs a job which produces a value. The task is retried and recovered
+// like any job and canceled on shutdown:
+//
+//	task := simplevisor.Go(supervisor, "fetch-rates", func(ctx context.Context) (Rates, error) {
+//		return client.FetchRates(ctx)
+//	}, simplevisor.WithRestart(simplevisor.RestartOnFailure, 3, time.Second))
+//
+//	rates, err := task.Await(ctx)
+//
+// # Worker Pools
+//
+// A pool runs N supervised replicas of the same handler. Replicas are named
+// "<pool>-<index>" and find their index in the context:
+//
+//	supervisor.RegisterPool("consumer", 4, func(ctx context.Context) error {
+//		index, _ := simplevisor.ReplicaIndex(ctx)
+//		return consume(ctx, partitions[index])
+//	}, simplevisor.WithRestart(simplevisor.RestartAlways, 5, time.Second))
+//
+//	// Add or gracefully remove replicas at runtime
+//	if err := supervisor.Scale("consumer", 8); err != nil {
+//		log.Printf("scale failed: %v", err)
+//	}
+//
+//	info, _ := supervisor.PoolStatus("consumer")
+//	log.Printf("%d/%d replicas running", info.Running, info.Size)
+//
+// Scaling down cancels the replicas with the highest indices and waits up to
+// the shutdown timeout for them to exit. A pool registered after Run starts
+// right away; its name and replica names must not be used by other processes.
+//
+// # Process Groups
+//
+// Labels group processes for bulk operations, e.g. after rotating the
+// credentials of a broker:
+//
+//	supervisor.Register("orders-consumer", consumeOrders,
+//		simplevisor.WithLabels(map[string]string{"broker": "rabbit"}))
+//	supervisor.RegisterPool("events-consumer", 4, consumeEvents,
+//		simplevisor.WithLabels(map[string]string{"broker": "rabbit"}))
+//
+//	rabbit := simplevisor.Selector{"broker": "rabbit"}
+//	for _, info := range supervisor.Processes(rabbit) {
+//		log.Printf("%s: %s", info.Name, info.Status)
+//	}
+//	if err := supervisor.RestartGroup(rabbit); err != nil {
+//		log.Printf("restart failed: %v", err)
+//	}
+//
+// A selector matches processes having all of its labels; an empty selector
+// matches every process. StopGroup stops the selected processes until
+// RestartGroup starts them again; restarted processes get a fresh restart budget.
+//
+// # Rolling Restart
+//
+// RollingRestart restarts processes a few at a time, e.g. after a config
+// rotation, instead of a full outage through Shutdown:
+//
+//	err := supervisor.RollingRestart(ctx, simplevisor.RollingRestartOptions{
+//		Selector:    simplevisor.Selector{"broker": "rabbit"},
+//		Concurrency: 2,
+//		Progress: func(p simplevisor.RollingRestartProgress) {
+//			log.Printf("restarted %s (%d/%d)", p.Process, p.Restarted, p.Total)
+//		},
+//	})
+//
+// A process is back once its new run started or, with a startup timeout, once
+// it called Ready. The rolling restart stops at the first process which doesn't
+// come back.
+//
+// # Control Socket
+//
+// A supervisor serves a local Unix socket to inspect and control its processes,
+// like supervisorctl, without opening an HTTP admin port:
+//
+//	supervisor := simplevisor.New(5*time.Second, logger,
+//		simplevisor.WithControlSocket("/run/app/control.sock"))
+//
+//	client := simplevisor.NewControlClient("/run/app/control.sock")
+//	processes, err := client.Status(ctx)
+//	err = client.Do(ctx, simplevisor.ControlRestart, "consumer")
+//
+// The socket is served from Run until shutdown and only accessible by its owner;
+// it is created in a private directory and moved into place once restricted.
+// Prefer a per-user directory such as $XDG_RUNTIME_DIR over a shared one like
+// /tmp. The app serves $XDG_RUNTIME_DIR/gohabit.sock by default and exposes it
+// with "gohabit ctl status|restart|stop|pause|resume|tail".
+//
+// # Pause and Resume
+//
+// A paused process stays registered and visible but doesn't run, e.g. to stop a
+// consumer from pulling messages during an incident:
+//
+//	if err := supervisor.Pause("consumer"); err != nil {
+//		log.Printf("pause failed: %v", err)
+//	}
+//	// ...
+//	_ = supervisor.Resume("consumer")
+//
+// Pause cancels the current run without counting it as a failure and waits up to
+// the shutdown timeout for it to exit. Resume starts the process with a fresh
+// restart budget.
+//
 // # Process Monitoring
 //
 // Monitor process status during runtime:
@@ -79,11 +309,103 @@
 //		// Process has stopped (will restart based on policy)
 //	case simplevisor.StatusRestarting:
 //		// Process is restarting after failure/completion
+//	case simplevisor.StatusStandby:
+//		// Process is waiting to acquire leadership
+//	case simplevisor.StatusPaused:
+//		// Process is paused until Resume
 //	}
 //
 //	// Get total number of registered processes
 //	count := supervisor.ProcessCount()
 //
+// The last exits of every process are kept to show the failure pattern of a
+// flapping process, 10 by default, see WithExitHistory:
+//
+//	info, _ := supervisor.GetProcessInfo("worker")
+//	for _, exit := range info.Exits {
+//		log.Printf("%s after %v: %v (restarted: %t)", exit.Time, exit.Duration, exit.Err, exit.Restarted)
+//	}
+//
+// # Goroutine Profiling
+//
+// Every run of a process or job is executed under pprof.Do with the labels
+// "process" and "attempt". Goroutines started by the process inherit the
+// labels, so CPU and goroutine profiles can be filtered per process:
+//
+//	go tool pprof -tagfocus process=consumer http://localhost:6060/debug/pprof/profile
+//
+// GoroutineCounts samples the number of live goroutines per process, which is
+// also shown by "ctl status". WithGoroutineLeakCheck warns when goroutines of a
+// run are still alive a grace period after the run returned:
+//
+//	supervisor := simplevisor.New(5*time.Second, logger,
+//		simplevisor.WithGoroutineLeakCheck(5*time.Second))
+//
+// # Chaos Mode
+//
+// Chaos mode validates restart policies in resilience drills by injecting
+// random faults into the runs of selected processes. It is disabled unless the
+// supervisor is created with WithChaos, and never meant for production:
+//
+//	supervisor := simplevisor.New(5*time.Second, logger, simplevisor.WithChaos(simplevisor.ChaosConfig{
+//		Seed:              42,
+//		Selector:          simplevisor.Selector{"broker": "rabbit"},
+//		CancelProbability: 0.2,
+//		PanicProbability:  0.05,
+//		DelayProbability:  0.1,
+//		MaxDelay:          5 * time.Second,
+//	}))
+//
+// Every injected fault is logged, published as an EventChaosCancel,
+// EventChaosPanic or EventChaosDelay event and counted in
+// simplevisor_chaos_faults_total. Panics and cancels are injected right around
+// the handler, inside every middleware; delays are injected before the run
+// starts, so a delayed process is not reported as running yet.
+//
+// # Events
+//
+// Subscribe to a stream of process lifecycle events such as starts, stops,
+// panics and restarts:
+//
+//	events, unsubscribe := supervisor.Subscribe(0)
+//	defer unsubscribe()
+//
+//	for event := range events {
+//		log.Printf("%s: %s", event.Process, event.Type)
+//	}
+//
+// Events are dropped for subscribers which don't keep up.
+//
+// # Alerting
+//
+// A Notifier is called on panics, exceeded restart limits and shutdown
+// timeouts. WebhookNotifier posts these events to a JSON webhook and is
+// compatible with Slack and Mattermost incoming webhooks. Failed requests are
+// retried with exponential backoff and a rate limit keeps crash loops from
+// flooding the channel:
+//
+//	webhook, err := simplevisor.NewWebhookNotifier(simplevisor.WebhookConfig{
+//		URL:        "https://hooks.slack.com/services/...",
+//		RateLimit:  10,
+//		RatePeriod: time.Minute,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//
+//	supervisor := simplevisor.New(5*time.Second, logger, simplevisor.WithNotifier(webhook))
+//
+// The payload is rendered from a WebhookPayload with text/template:
+//
+//	Template: `{"text": {{json .Text}}, "channel": "#alerts", "username": "gohabit"}`
+//
+// # Testing
+//
+// All timing of the supervisor goes through a Clock which can be replaced with
+// simplevisor.WithClock(). The simplevisortest package ships a fake clock and
+// helpers to await statuses and events and to assert restart counts, so crash
+// behaviour can be tested without sleeping.
+//
 // # Graceful Shutdown
 //
 // Simplevisor provides coordinated shutdown with timeout protection:
@@ -95,10 +417,33 @@
 //	supervisor.Shutdown()
 //
 // During shutdown:
-// 1. All process contexts are cancelled
-// 2. Processes should handle ctx.Done() and return gracefully
-// 3. Supervisor waits for all processes to finish (with timeout)
-// 4. Optional teardown callback is executed
+// 1. The Draining channel of every process is closed and failed processes are no longer restarted
+// 2. All process contexts are cancelled, after the drain period if one is set
+// 3. Processes should handle ctx.Done() and return gracefully
+// 4. Supervisor waits for all processes to finish (with timeout)
+// 5. Optional teardown callback is executed
+//
+// With a drain period, processes stop taking new work first and finish their
+// in-flight work before their contexts are cancelled:
+//
+//	supervisor := simplevisor.New(30*time.Second, logger, simplevisor.WithDrainPeriod(20*time.Second))
+//
+//	supervisor.Register("consumer", func(ctx context.Context) error {
+//		for {
+//			select {
+//			case <-simplevisor.Draining(ctx):
+//				return nil // Stop fetching, in-flight messages are done
+//			case msg := <-messages:
+//				handle(ctx, msg)
+//			}
+//		}
+//	})
+//
+// The drain period is part of the shutdown timeout and capped at 90% of it.
+//
+// If the shutdown timeout is exceeded, the supervisor logs which processes and
+// jobs didn't exit and how long each has been running. Create the supervisor
+// with WithStuckGoroutineDump() to attach their goroutine stacks as well.
 //
 // # Context-Based Cancellation
 //
@@ -121,6 +466,56 @@
 //		}
 //	}
 //
+// # Process Context
+//
+// The context passed to a process carries the metadata of its current run:
+//
+//	func worker(ctx context.Context) error {
+//		logger := simplevisor.Logger(ctx) // tagged with process_name and attempt
+//		logger.Info("worker started",
+//			slog.String("name", simplevisor.ProcessName(ctx)),
+//			slog.Int("attempt", simplevisor.Attempt(ctx)),
+//			slog.Time("started_at", simplevisor.StartedAt(ctx)))
+//		...
+//	}
+//
+// Outside a supervised process Logger() falls back to slog.Default().
+//
+// Cleanup registers a function which is called when the run ends, whether the
+// process returns, fails, panics or is stopped. Functions are called in
+// reverse order, so a half-initialized process releases what it acquired
+// before the next restart:
+//
+//	func worker(ctx context.Context) error {
+//		conn, err := db.Connect(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		simplevisor.Cleanup(ctx, conn.Close)
+//		...
+//	}
+//
+// Cleanup errors are logged and kept in ExitRecord.CleanupErr; they don't fail
+// the run.
+//
+// # Child Goroutines
+//
+// Helper goroutines started with a plain go statement escape panic recovery
+// and shutdown. Spawn starts a child goroutine which is supervised with the
+// run of its process:
+//
+//	func worker(ctx context.Context) error {
+//		simplevisor.Spawn(ctx, "heartbeat", func(ctx context.Context) error {
+//			return heartbeat(ctx)
+//		})
+//		return consume(ctx)
+//	}
+//
+// A panic or error of a child fails the run and cancels its context. The run is
+// finished only when all of its children have exited, so graceful shutdown waits
+// for them too. ProcessInfo.Children and "ctl status" show the number of live
+// children.
+//
 // # Thread Safety
 //
 // Simplevisor is thread-safe for:
@@ -152,6 +547,29 @@
 //   - Max restarts: 3
 //   - Restart delay: 1 second
 //
+// # Declarative Configuration
+//
+// Restart settings can be loaded from a config file with pkg/config instead of being set in code.
+// Settings are keyed by process or pool name, override the options given in code, and unset
+// fields keep their value:
+//
+//	shutdown_timeout: 10s
+//	processes:
+//	  consumer:
+//	    restart: on_failure
+//	    max_restarts: 5
+//	    delay: 2s
+//
+//	var cfg simplevisor.Config
+//	if err := config.LoadConfig("APP", path, builtin, &cfg); err != nil {
+//	    return err
+//	}
+//	supervisor := simplevisor.NewFromConfig(cfg, logger)
+//	supervisor.Register("consumer", consumer, simplevisor.WithRestart(simplevisor.RestartAlways, 3, time.Second))
+//	if err := supervisor.Start(); err != nil {
+//	    return err // e.g. a typo in a process name
+//	}
+//
 // # Error Handling
 //
 // Processes should return errors for failure conditions:
@@ -191,11 +609,20 @@
 // Key metrics include:
 // - simplevisor_processes_running: Currently running processes (UpDownCounter)
 // - simplevisor_process_restart_count: Current restart count per process (Gauge)
-// - simplevisor_process_status: Process status (Gauge: 1=running, 0=stopped, -1=restarting)
+// - simplevisor_process_status: Process status (Gauge: 1=running, 0=stopped, -1=restarting, 2=standby, 3=paused)
 // - simplevisor_process_started_total: Process start events (Counter)
 // - simplevisor_process_stopped_total: Process stop events by reason (Counter)
 // - simplevisor_process_panics_total: Process panic events (Counter)
 // - simplevisor_restart_limit_exceeded_total: Critical restart failures (Counter)
+// - simplevisor_pool_size: Desired number of replicas per pool (Gauge)
+// - simplevisor_pool_replicas_running: Running replicas per pool (Gauge)
+// - simplevisor_process_breaker_state: Circuit breaker state (Gauge: 0=closed, 1=open, 2=half_open)
+// - simplevisor_process_breaker_opened_total: Circuit breaker openings (Counter)
+// - simplevisor_chaos_faults_total: Faults injected by chaos mode by fault (Counter)
+// - simplevisor_goroutine_leaks_total: Goroutines alive after their process run returned (Counter)
+//
+// Process labels set with WithLabels are attached to the process metrics as
+// "label_<key>" attributes.
 //
 // Metrics are automatically recorded when EnableMetrics() is called.
 //
@@ -208,4 +635,14 @@
 // - Shutdown progress and completion
 //
 // Pass a custom logger to New() or use nil for default console output.
+//
+// A crash-looping process repeats the same log lines on every restart.
+// WithLogDedup collapses identical supervisor log records about a process into
+// a periodic summary with a count, and WithProcessLogLevel overrides the log
+// level of a single process:
+//
+//	supervisor := simplevisor.New(5*time.Second, logger,
+//		simplevisor.WithLogDedup(time.Minute),
+//		simplevisor.WithProcessLogLevel("noisy-consumer", slog.LevelWarn),
+//		simplevisor.WithProcessLogLevel("new-worker", slog.LevelDebug))
 package simplevisor
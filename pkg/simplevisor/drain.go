@@ -0,0 +1,53 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithDrainPeriod delays the cancellation of process contexts on shutdown.
+// Shutdown first closes the Draining channel of every process and waits up to period for the
+// processes to exit, then cancels their contexts. The drain period is part of the shutdown timeout;
+// it is capped at 90% of the timeout to leave processes time to react to the cancellation.
+func WithDrainPeriod(period time.Duration) SupervisorOption {
+	return func(s *Supervisor) {
+		s.drainPeriod = period
+	}
+}
+
+// canSpawnLocked reports whether process goroutines may be started: the supervisor runs and
+// shutdown hasn't started, so it never adds to processWg while shutdown waits. The caller must hold s.lock.
+func (s *Supervisor) canSpawnLocked() bool {
+	return s.running && s.shutDownCtx.Err() == nil && !isClosed(s.draining)
+}
+
+// drain waits for the draining processes to exit until the drain period is over.
+func (s *Supervisor) drain(done <-chan struct{}) {
+	period := min(s.drainPeriod, s.shutdownTimeout-s.shutdownTimeout/10)
+	if period <= 0 {
+		return
+	}
+
+	s.logger.Info("draining processes", slog.Duration("drain_period", period))
+
+	select {
+	case <-done:
+	case <-s.clock.After(period):
+		s.logger.Info("drain period is over, cancelling processes")
+	}
+}
+
+// untilDraining returns a context which is also cancelled once the supervisor starts draining.
+func (s *Supervisor) untilDraining(ctx context.Context) (context.Context, context.CancelFunc) {
+	drainCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-s.draining:
+			cancel()
+		case <-drainCtx.Done():
+		}
+	}()
+
+	return drainCtx, cancel
+}
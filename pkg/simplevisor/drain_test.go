@@ -0,0 +1,191 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_DrainBeforeCancel(t *testing.T) {
+	s := New(time.Second, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithDrainPeriod(500*time.Millisecond))
+
+	var cancelledBeforeDrained atomic.Bool
+	s.Register("consumer", func(ctx context.Context) error {
+		select {
+		case <-Draining(ctx):
+		case <-ctx.Done():
+			cancelledBeforeDrained.Store(true)
+			return ctx.Err()
+		}
+
+		// Finish in-flight work while the context is still alive
+		time.Sleep(20 * time.Millisecond)
+		if ctx.Err() != nil {
+			cancelledBeforeDrained.Store(true)
+		}
+		return nil
+	}, WithRestart(RestartAlways, 0, 10*time.Millisecond))
+	s.Run()
+
+	waitForStatus(t, s, "consumer", StatusRunning, time.Second)
+
+	start := time.Now()
+	s.Shutdown()
+	elapsed := time.Since(start)
+
+	if cancelledBeforeDrained.Load() {
+		t.Error("Expected context to stay alive while draining")
+	}
+	if elapsed >= 500*time.Millisecond {
+		t.Errorf("Expected shutdown to finish once the process exited, took %v", elapsed)
+	}
+	if attempts := s.processes["consumer"].attempts; attempts != 1 {
+		t.Errorf("Expected process not to be restarted while draining, got %d runs", attempts)
+	}
+}
+
+func TestSupervisor_DrainPeriodExpires(t *testing.T) {
+	s := New(time.Second, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithDrainPeriod(50*time.Millisecond))
+
+	var cancelledAfter atomic.Int64
+	s.Register("slow", func(ctx context.Context) error {
+		<-Draining(ctx)
+		drainedAt := time.Now()
+		<-ctx.Done()
+		cancelledAfter.Store(int64(time.Since(drainedAt)))
+		return ctx.Err()
+	})
+	s.Run()
+
+	waitForStatus(t, s, "slow", StatusRunning, time.Second)
+	s.Shutdown()
+
+	if got := time.Duration(cancelledAfter.Load()); got < 40*time.Millisecond {
+		t.Errorf("Expected context to be cancelled after the drain period, got %v", got)
+	}
+}
+
+func TestSupervisor_DrainPeriodCappedByTimeout(t *testing.T) {
+	s := New(100*time.Millisecond, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithDrainPeriod(time.Hour))
+
+	var exited atomic.Bool
+	s.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		exited.Store(true)
+		return ctx.Err()
+	})
+	s.Run()
+
+	waitForStatus(t, s, "slow", StatusRunning, time.Second)
+	s.Shutdown()
+
+	if !exited.Load() {
+		t.Error("Expected process to be cancelled before the shutdown timeout")
+	}
+}
+
+func TestSupervisor_DrainDoesNotWaitForIdleProcesses(t *testing.T) {
+	s := New(2*time.Second, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithDrainPeriod(time.Second))
+
+	// A process cooling off with an open breaker
+	s.Register("broken", func(ctx context.Context) error {
+		return errors.New("database unavailable")
+	}, WithRestart(RestartOnFailure, 1, 10*time.Millisecond), WithCircuitBreaker(time.Hour, 0))
+
+	// A process waiting for a lease held by another replica
+	locker := NewMemoryLocker()
+	lease, err := locker.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to acquire lease: %v", err)
+	}
+	defer func() { _ = lease.Release() }()
+	s.Register("follower", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithLeaderElection(locker))
+
+	s.Run()
+
+	waitForStatus(t, s, "follower", StatusStandby, time.Second)
+	deadline := time.Now().Add(time.Second)
+	for s.getBreakerState("broken") != BreakerOpen && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s.getBreakerState("broken") != BreakerOpen {
+		t.Fatal("Breaker should have opened")
+	}
+
+	start := time.Now()
+	s.Shutdown()
+
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Errorf("Expected shutdown not to wait out the drain period without running processes, took %v", elapsed)
+	}
+}
+
+func TestSupervisor_NoProcessStartsWhileDraining(t *testing.T) {
+	s := New(2*time.Second, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithDrainPeriod(time.Second))
+
+	handler := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	s.Register("worker", handler)
+	s.Register("paused", handler)
+	s.RegisterPool("consumer", 1, handler)
+	s.Run()
+
+	waitForStatus(t, s, "worker", StatusRunning, time.Second)
+	if err := s.Pause("paused"); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+
+	shutdown := make(chan struct{})
+	go func() {
+		s.Shutdown()
+		close(shutdown)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.lock.Lock()
+		draining := isClosed(s.draining)
+		s.lock.Unlock()
+		if draining {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for action, err := range map[string]error{
+		"restart": s.Restart("worker"),
+		"resume":  s.Resume("paused"),
+		"scale":   s.Scale("consumer", 2),
+	} {
+		if !errors.Is(err, ErrDraining) {
+			t.Errorf("Expected %s to fail with ErrDraining, got %v", action, err)
+		}
+	}
+
+	s.RegisterPool("late", 1, handler)
+	if info, _ := s.GetProcessInfo(replicaName("late", 0)); info.Attempts != 0 {
+		t.Error("Pool registered while draining should not start")
+	}
+
+	<-shutdown
+}
+
+func TestDraining_OutsideProcess(t *testing.T) {
+	if Draining(context.Background()) != nil {
+		t.Error("Expected nil draining channel outside a process")
+	}
+}
@@ -0,0 +1,131 @@
+package simplevisor
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultEventBuffer is the channel buffer size of a subscription
+const DefaultEventBuffer = 64
+
+// EventType is the kind of a supervisor event
+type EventType int
+
+const (
+	EventStarted              EventType = iota // A process run started
+	EventStopped                               // A process run finished, Err is set on failure
+	EventPanic                                 // A process run panicked, Err is a *PanicError
+	EventRestarting                            // A process is waiting for its restart delay
+	EventRestartLimitExceeded                  // A process exceeded its restart limit and is stopped
+	EventBreakerOpened                         // A process circuit breaker opened
+	EventBreakerClosed                         // A process circuit breaker closed
+	EventLeadershipAcquired                    // A leader-elected process acquired its lease
+	EventLeadershipLost                        // A leader-elected process lost its lease
+	EventShutdownTimeout                       // Graceful shutdown timed out
+	EventPaused                                // A process was paused
+	EventResumed                               // A paused process was resumed
+	EventReady                                 // A process run signalled readiness, see Ready
+	EventChaosCancel                           // Chaos mode cancelled a process run
+	EventChaosPanic                            // Chaos mode injected a panic into a process run
+	EventChaosDelay                            // Chaos mode delayed the start of a process run
+)
+
+func (e EventType) String() string {
+	switch e {
+	case EventStarted:
+		return "started"
+	case EventStopped:
+		return "stopped"
+	case EventPanic:
+		return "panic"
+	case EventRestarting:
+		return "restarting"
+	case EventRestartLimitExceeded:
+		return "restart_limit_exceeded"
+	case EventBreakerOpened:
+		return "breaker_opened"
+	case EventBreakerClosed:
+		return "breaker_closed"
+	case EventLeadershipAcquired:
+		return "leadership_acquired"
+	case EventLeadershipLost:
+		return "leadership_lost"
+	case EventShutdownTimeout:
+		return "shutdown_timeout"
+	case EventPaused:
+		return "paused"
+	case EventResumed:
+		return "resumed"
+	case EventReady:
+		return "ready"
+	case EventChaosCancel:
+		return "chaos_cancel"
+	case EventChaosPanic:
+		return "chaos_panic"
+	case EventChaosDelay:
+		return "chaos_delay"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes something that happened to a supervised process.
+type Event struct {
+	Type         EventType
+	Process      string // Empty for supervisor-wide events
+	Time         time.Time
+	Err          error
+	RestartCount int
+}
+
+// eventBus fans out events to subscribers without blocking the supervisor.
+type eventBus struct {
+	lock        sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// Subscribe returns a channel of supervisor events and a function to unsubscribe.
+// Events are dropped for a subscriber whose buffer is full.
+// Unsubscribing closes the channel.
+func (s *Supervisor) Subscribe(buffer int) (<-chan Event, func()) {
+	if buffer <= 0 {
+		buffer = DefaultEventBuffer
+	}
+
+	ch := make(chan Event, buffer)
+
+	s.events.lock.Lock()
+	if s.events.subscribers == nil {
+		s.events.subscribers = make(map[chan Event]struct{})
+	}
+	s.events.subscribers[ch] = struct{}{}
+	s.events.lock.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.events.lock.Lock()
+			defer s.events.lock.Unlock()
+
+			delete(s.events.subscribers, ch)
+			close(ch)
+		})
+	}
+}
+
+// publish sends an event to all subscribers.
+func (s *Supervisor) publish(event Event) {
+	event.Time = s.clock.Now()
+
+	s.events.lock.Lock()
+	defer s.events.lock.Unlock()
+
+	for ch := range s.events.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+
+	s.notify(event)
+}
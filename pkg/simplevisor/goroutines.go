@@ -0,0 +1,112 @@
+package simplevisor
+
+import (
+	"bufio"
+	"bytes"
+	"log/slog"
+	"regexp"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PprofLabelProcess is the pprof label holding the name of the process of a goroutine
+	PprofLabelProcess = "process"
+	// PprofLabelAttempt is the pprof label holding the attempt of the run which started a goroutine
+	PprofLabelAttempt = "attempt"
+)
+
+// pprofLabel matches a "key":"value" pair in the labels of a goroutine profile
+var pprofLabel = regexp.MustCompile(`("(?:[^"\\]|\\.)*"):("(?:[^"\\]|\\.)*")`)
+
+// WithGoroutineLeakCheck warns when goroutines started by a process run are still alive
+// the grace period after the run returned. Every check samples the goroutine profile.
+func WithGoroutineLeakCheck(grace time.Duration) SupervisorOption {
+	return func(s *Supervisor) {
+		s.leakCheckGrace = grace
+	}
+}
+
+// GoroutineCounts returns the number of live goroutines of each process and job,
+// sampled from the goroutine profile. Processes and jobs run with the pprof labels
+// PprofLabelProcess and PprofLabelAttempt, which goroutines inherit from the goroutine starting them.
+func (s *Supervisor) GoroutineCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, group := range labelledGoroutines() {
+		if name, ok := group.labels[PprofLabelProcess]; ok {
+			counts[name] += group.count
+		}
+	}
+
+	return counts
+}
+
+// checkGoroutineLeak warns about goroutines of a finished run which are still alive.
+func (s *Supervisor) checkGoroutineLeak(name string, attempt int) {
+	count := 0
+	for _, group := range labelledGoroutines() {
+		if group.labels[PprofLabelProcess] == name && group.labels[PprofLabelAttempt] == strconv.Itoa(attempt) {
+			count += group.count
+		}
+	}
+
+	if count == 0 {
+		return
+	}
+
+	s.logger.Warn("process returned but its goroutines are still alive",
+		slog.String("process_name", name),
+		slog.Int("attempt", attempt),
+		slog.Int("goroutines", count),
+		slog.Duration("grace_period", s.leakCheckGrace))
+	s.metrics.recordGoroutineLeak(name, count)
+}
+
+// goroutineGroup is a set of goroutines with the same stack and labels in the goroutine profile.
+type goroutineGroup struct {
+	count  int
+	labels map[string]string
+}
+
+// labelledGoroutines returns the groups of goroutines which have pprof labels.
+func labelledGoroutines() []goroutineGroup {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+
+	// A group starts with "<count> @ <pc>..." and lists its labels as
+	// # labels: {"attempt":"1", "process":"consumer"}
+	var groups []goroutineGroup
+	count := 0
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if fields := strings.Fields(line); len(fields) > 1 && fields[1] == "@" {
+			count, _ = strconv.Atoi(fields[0])
+			continue
+		}
+
+		labels, ok := strings.CutPrefix(line, "# labels: ")
+		if !ok || count == 0 {
+			continue
+		}
+
+		group := goroutineGroup{count: count, labels: make(map[string]string)}
+		for _, match := range pprofLabel.FindAllStringSubmatch(labels, -1) {
+			key, keyErr := strconv.Unquote(match[1])
+			value, valueErr := strconv.Unquote(match[2])
+			if keyErr == nil && valueErr == nil {
+				group.labels[key] = value
+			}
+		}
+		groups = append(groups, group)
+		count = 0
+	}
+
+	return groups
+}
@@ -0,0 +1,148 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"runtime/pprof"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRunProcess_PprofLabels(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	labels := make(chan [2]string, 1)
+	s.Register("labelled", func(ctx context.Context) error {
+		process, _ := pprof.Label(ctx, PprofLabelProcess)
+		attempt, _ := pprof.Label(ctx, PprofLabelAttempt)
+		labels <- [2]string{process, attempt}
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	select {
+	case got := <-labels:
+		if got != [2]string{"labelled", "1"} {
+			t.Errorf("expected labels process=labelled attempt=1, got %v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("process did not start")
+	}
+}
+
+// awaitGoroutineCounts polls GoroutineCounts until the counts of the given processes match.
+// Goroutines are counted until they have fully exited, which is slightly after they signal it.
+func awaitGoroutineCounts(t *testing.T, s *Supervisor, expected map[string]int) {
+	t.Helper()
+
+	var counts map[string]int
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		counts = s.GoroutineCounts()
+		matches := true
+		for name, count := range expected {
+			matches = matches && counts[name] == count
+		}
+		if matches {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("expected goroutine counts %v, got %v", expected, counts)
+}
+
+// capturingClock is the real clock, except that AfterFunc calls are captured instead of scheduled.
+type capturingClock struct {
+	realClock
+	calls chan func()
+}
+
+func (c *capturingClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.calls <- f
+	return time.NewTimer(0)
+}
+
+func TestSupervisor_GoroutineCounts(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	started := make(chan struct{})
+	s.Register("spawner", func(ctx context.Context) error {
+		for range 3 {
+			go func() {
+				<-ctx.Done()
+			}()
+		}
+		close(started)
+		<-ctx.Done()
+		return nil
+	})
+	s.Register("single", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+
+	<-started
+	awaitGoroutineCounts(t, s, map[string]int{"spawner": 4, "single": 1})
+
+	for _, process := range s.controlProcesses() {
+		if process.Name == "spawner" && process.Goroutines != 4 {
+			t.Errorf("expected 4 goroutines in control status, got %d", process.Goroutines)
+		}
+	}
+
+	s.Shutdown()
+
+	// The helpers of spawner aren't tracked and exit on their own after shutdown
+	awaitGoroutineCounts(t, s, map[string]int{"spawner": 0, "single": 0})
+}
+
+func TestWithGoroutineLeakCheck(t *testing.T) {
+	buf := &syncBuffer{}
+	clock := &capturingClock{calls: make(chan func(), 2)}
+	s := New(time.Second, slog.New(slog.NewJSONHandler(buf, nil)),
+		WithClock(clock), WithGoroutineLeakCheck(time.Minute))
+
+	release := make(chan struct{})
+	defer close(release)
+	s.Register("leaky", func(ctx context.Context) error {
+		go func() {
+			<-release
+		}()
+		return nil
+	})
+	s.Register("clean", func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			close(done)
+		}()
+		<-done
+		return nil
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	// Both runs return right away and schedule their leak check, which is run
+	// once the goroutines of the clean process are gone
+	checks := []func(){<-clock.calls, <-clock.calls}
+	awaitGoroutineCounts(t, s, map[string]int{"leaky": 1, "clean": 0})
+	for _, check := range checks {
+		check()
+	}
+
+	var names []string
+	for _, record := range buf.records(t, "process returned but its goroutines are still alive") {
+		group := record["supervisor"].(map[string]any)
+		names = append(names, group["process_name"].(string))
+		if group["goroutines"] != float64(1) {
+			t.Errorf("expected 1 leaked goroutine, got %v", group["goroutines"])
+		}
+	}
+	if !slices.Equal(names, []string{"leaky"}) {
+		t.Errorf("expected leak warning for leaky only, got %v", names)
+	}
+}
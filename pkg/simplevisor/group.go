@@ -0,0 +1,236 @@
+package simplevisor
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+	"strings"
+)
+
+// Selector selects processes by their labels.
+// A process matches if it has all labels of the selector; an empty selector matches every process.
+type Selector map[string]string
+
+// Matches reports whether labels contain every label of the selector.
+func (sel Selector) Matches(labels map[string]string) bool {
+	for key, value := range sel {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WithLabels attaches labels to the process, e.g. {"broker": "rabbit"}.
+// Labels select processes in group operations and are attached to the process metrics.
+func WithLabels(labels map[string]string) Option {
+	return func(p *Process) {
+		if p.labels == nil {
+			p.labels = make(map[string]string, len(labels))
+		}
+		maps.Copy(p.labels, labels)
+	}
+}
+
+// Processes returns a snapshot of the processes matching selector, sorted by name.
+func (s *Supervisor) Processes(selector Selector) []ProcessInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var infos []ProcessInfo
+	for _, process := range s.processes {
+		if process.removed || !selector.Matches(process.labels) {
+			continue
+		}
+		infos = append(infos, process.info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+// StopGroup stops the processes matching selector and waits up to the shutdown timeout for them to exit.
+// Stopped processes stay registered and aren't restarted until RestartGroup selects them.
+func (s *Supervisor) StopGroup(selector Selector) error {
+	s.lock.Lock()
+	names := s.selectLocked(selector)
+	stopping := s.stopLocked(names, true)
+	s.lock.Unlock()
+
+	s.logger.Info("stopping process group",
+		slog.Any("selector", selector),
+		slog.Any("processes", names))
+
+	if pending := s.waitForExit(stopping); len(pending) > 0 {
+		return fmt.Errorf("processes %s did not exit within %v", strings.Join(pending, ", "), s.shutdownTimeout)
+	}
+
+	return nil
+}
+
+// RestartGroup restarts the processes matching selector, including the ones stopped by StopGroup.
+// Paused processes stay paused.
+// Running processes are stopped first, waiting up to the shutdown timeout for them to exit.
+// Restarted processes start with a fresh restart budget.
+// If the supervisor isn't running yet, the processes are started by Run.
+func (s *Supervisor) RestartGroup(selector Selector) error {
+	s.lock.Lock()
+	names := s.selectLocked(selector)
+	s.lock.Unlock()
+
+	s.logger.Info("restarting process group",
+		slog.Any("selector", selector),
+		slog.Any("processes", names))
+
+	return s.restart(names)
+}
+
+// restart stops the named processes and starts them again with a fresh restart budget.
+// Returns ErrDraining once shutdown started.
+func (s *Supervisor) restart(names []string) error {
+	s.lock.Lock()
+	if isClosed(s.draining) {
+		s.lock.Unlock()
+		return ErrDraining
+	}
+	stopping := s.stopLocked(names, false)
+	s.lock.Unlock()
+
+	pending := s.waitForExit(stopping)
+
+	s.lock.Lock()
+	if s.canSpawnLocked() {
+		for _, name := range names {
+			process, ok := s.processes[name]
+			if !ok || process.removed || process.stopped || process.paused ||
+				(process.done != nil && !isClosed(process.done)) {
+				continue
+			}
+
+			s.resetRestartBudgetLocked(name)
+			s.spawnLocked(name)
+		}
+	}
+	s.lock.Unlock()
+
+	if len(pending) > 0 {
+		return fmt.Errorf("processes %s did not exit within %v, not restarted",
+			strings.Join(pending, ", "), s.shutdownTimeout)
+	}
+
+	return nil
+}
+
+// selectLocked returns the names of the processes matching selector, sorted. The caller must hold s.lock.
+func (s *Supervisor) selectLocked(selector Selector) []string {
+	var names []string
+	for name, process := range s.processes {
+		if !process.removed && selector.Matches(process.labels) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// stopLocked cancels the named processes which are running and marks them stopped or not.
+// It returns the done channels of the cancelled processes. The caller must hold s.lock.
+func (s *Supervisor) stopLocked(names []string, stopped bool) map[string]chan struct{} {
+	stopping := make(map[string]chan struct{})
+	for _, name := range names {
+		process, ok := s.processes[name]
+		if !ok {
+			continue
+		}
+
+		process.stopped = stopped
+		s.processes[name] = process
+
+		if process.done != nil && !isClosed(process.done) {
+			process.cancel()
+			stopping[name] = process.done
+		}
+	}
+
+	return stopping
+}
+
+// resetRestartBudgetLocked resets the restart count and circuit breaker of a process.
+// The caller must hold s.lock.
+func (s *Supervisor) resetRestartBudgetLocked(name string) {
+	process, ok := s.processes[name]
+	if !ok {
+		return
+	}
+
+	process.restartCount = 0
+	process.probeFailures = 0
+	if process.breaker != BreakerClosed {
+		process.breaker = BreakerClosed
+		s.metrics.recordBreakerState(name, BreakerClosed)
+	}
+	s.processes[name] = process
+}
+
+// waitForExit waits up to the shutdown timeout for the goroutines of processes to exit.
+// It returns the names of the processes which are still running, sorted.
+func (s *Supervisor) waitForExit(exiting map[string]chan struct{}) []string {
+	timeout := s.clock.After(s.shutdownTimeout)
+	for _, done := range exiting {
+		select {
+		case <-done:
+		case <-timeout:
+			var pending []string
+			for name, d := range exiting {
+				if !isClosed(d) {
+					pending = append(pending, name)
+				}
+			}
+			sort.Strings(pending)
+
+			return pending
+		}
+	}
+
+	return nil
+}
+
+// Stop stops a process and waits up to the shutdown timeout for it to exit.
+// The process stays registered and isn't restarted until Restart, see StopGroup.
+func (s *Supervisor) Stop(name string) error {
+	s.lock.Lock()
+	if process, ok := s.processes[name]; !ok || process.removed {
+		s.lock.Unlock()
+		return fmt.Errorf("process %s not found", name)
+	}
+	stopping := s.stopLocked([]string{name}, true)
+	s.lock.Unlock()
+
+	s.logger.Info("stopping process", slog.String("process_name", name))
+
+	if pending := s.waitForExit(stopping); len(pending) > 0 {
+		return fmt.Errorf("process %s did not exit within %v", name, s.shutdownTimeout)
+	}
+
+	return nil
+}
+
+// Restart restarts a process with a fresh restart budget, including a process stopped by Stop.
+// See RestartGroup.
+func (s *Supervisor) Restart(name string) error {
+	s.lock.Lock()
+	process, ok := s.processes[name]
+	s.lock.Unlock()
+
+	if !ok || process.removed {
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	s.logger.Info("restarting process", slog.String("process_name", name))
+	return s.restart([]string{name})
+}
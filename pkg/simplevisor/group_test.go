@@ -0,0 +1,186 @@
+package simplevisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSelector_Matches(t *testing.T) {
+	labels := map[string]string{"broker": "rabbit", "team": "orders"}
+
+	tests := []struct {
+		name     string
+		selector Selector
+		expected bool
+	}{
+		{name: "Empty selector", selector: Selector{}, expected: true},
+		{name: "Single label", selector: Selector{"broker": "rabbit"}, expected: true},
+		{name: "All labels", selector: Selector{"broker": "rabbit", "team": "orders"}, expected: true},
+		{name: "Different value", selector: Selector{"broker": "kafka"}, expected: false},
+		{name: "Missing label", selector: Selector{"broker": "rabbit", "region": "eu"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.selector.Matches(labels); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSupervisor_Processes(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	handler := func(ctx context.Context) error { return nil }
+	s.Register("http", handler, WithLabels(map[string]string{"kind": "server"}))
+	s.Register("orders", handler, WithLabels(map[string]string{"broker": "rabbit"}))
+	s.RegisterPool("events", 2, handler, WithLabels(map[string]string{"broker": "rabbit"}))
+
+	infos := s.Processes(Selector{"broker": "rabbit"})
+	if len(infos) != 3 {
+		t.Fatalf("Expected 3 processes, got %d", len(infos))
+	}
+
+	expected := []string{"events-0", "events-1", "orders"}
+	for i, info := range infos {
+		if info.Name != expected[i] {
+			t.Errorf("Expected process %s at %d, got %s", expected[i], i, info.Name)
+		}
+		if info.Labels["broker"] != "rabbit" {
+			t.Errorf("Expected labels in info of %s, got %v", info.Name, info.Labels)
+		}
+	}
+
+	if all := s.Processes(nil); len(all) != 4 {
+		t.Errorf("Expected nil selector to match all 4 processes, got %d", len(all))
+	}
+
+	// Labels in the snapshot are a copy
+	infos[0].Labels["broker"] = "kafka"
+	if len(s.Processes(Selector{"broker": "rabbit"})) != 3 {
+		t.Error("Modifying a snapshot should not change the process labels")
+	}
+}
+
+func TestSupervisor_StopGroup(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	handler := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	s.Register("http", handler, WithRestart(RestartAlways, 0, 10*time.Millisecond))
+	s.Register("orders", handler, WithRestart(RestartAlways, 0, 10*time.Millisecond),
+		WithLabels(map[string]string{"broker": "rabbit"}))
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "http", StatusRunning, time.Second)
+	waitForStatus(t, s, "orders", StatusRunning, time.Second)
+
+	if err := s.StopGroup(Selector{"broker": "rabbit"}); err != nil {
+		t.Fatalf("StopGroup failed: %v", err)
+	}
+
+	if status, _ := s.GetProcessStatus("orders"); status != StatusStopped {
+		t.Errorf("Expected orders to be stopped, got %v", status)
+	}
+	if !s.IsRunning("http") {
+		t.Error("Expected http to keep running")
+	}
+
+	// The stopped process is not restarted
+	time.Sleep(50 * time.Millisecond)
+	if status, _ := s.GetProcessStatus("orders"); status != StatusStopped {
+		t.Errorf("Expected orders to stay stopped, got %v", status)
+	}
+
+	if err := s.RestartGroup(Selector{"broker": "rabbit"}); err != nil {
+		t.Fatalf("RestartGroup failed: %v", err)
+	}
+	waitForStatus(t, s, "orders", StatusRunning, time.Second)
+}
+
+func TestSupervisor_StopGroupBeforeRun(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var runs atomic.Int32
+	s.Register("orders", func(ctx context.Context) error {
+		runs.Add(1)
+		<-ctx.Done()
+		return nil
+	}, WithLabels(map[string]string{"broker": "rabbit"}))
+
+	if err := s.StopGroup(Selector{"broker": "rabbit"}); err != nil {
+		t.Fatalf("StopGroup failed: %v", err)
+	}
+
+	s.Run()
+	defer s.Shutdown()
+
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Errorf("Expected stopped process not to be started by Run, got %d runs", runs.Load())
+	}
+}
+
+func TestSupervisor_RestartGroup(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var rabbitRuns, httpRuns atomic.Int32
+	s.RegisterPool("consumer", 2, func(ctx context.Context) error {
+		rabbitRuns.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithRestart(RestartOnFailure, 3, 10*time.Millisecond), WithLabels(map[string]string{"broker": "rabbit"}))
+	s.Register("http", func(ctx context.Context) error {
+		httpRuns.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	waitForPoolRunning(t, s, "consumer", 2, time.Second)
+	waitForStatus(t, s, "http", StatusRunning, time.Second)
+
+	if err := s.RestartGroup(Selector{"broker": "rabbit"}); err != nil {
+		t.Fatalf("RestartGroup failed: %v", err)
+	}
+	waitForPoolRunning(t, s, "consumer", 2, time.Second)
+
+	if got := rabbitRuns.Load(); got != 4 {
+		t.Errorf("Expected 4 consumer runs, got %d", got)
+	}
+	if got := httpRuns.Load(); got != 1 {
+		t.Errorf("Expected http not to be restarted, got %d runs", got)
+	}
+
+	for _, info := range s.Processes(Selector{"broker": "rabbit"}) {
+		if info.RestartCount != 0 {
+			t.Errorf("Expected fresh restart budget for %s, got %d", info.Name, info.RestartCount)
+		}
+	}
+}
+
+func TestSupervisor_RestartGroupTimeout(t *testing.T) {
+	s := createTestSupervisor(50 * time.Millisecond)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	s.Register("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	}, WithLabels(map[string]string{"broker": "rabbit"}))
+	s.Run()
+
+	waitForStatus(t, s, "stuck", StatusRunning, time.Second)
+
+	if err := s.RestartGroup(Selector{"broker": "rabbit"}); err == nil {
+		t.Error("Expected error for a process which does not exit")
+	}
+}
@@ -0,0 +1,70 @@
+package simplevisor
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultExitHistorySize is the number of exits kept per process
+const DefaultExitHistorySize = 10
+
+// ExitRecord describes a finished run of a process.
+type ExitRecord struct {
+	Time      time.Time     // When the run finished
+	Duration  time.Duration // How long the run took
+	Err       error         // Nil if the run succeeded, a *PanicError if it panicked
+	Panic     any           // The panic value if the run panicked
+	Restarted bool          // Whether a restart followed the exit
+	// CleanupErr joins the errors of the functions registered with Cleanup, nil if all succeeded
+	CleanupErr error
+}
+
+// WithExitHistory sets the number of exits kept for the process, see ProcessInfo.Exits.
+// A size <= 0 disables the history.
+func WithExitHistory(size int) Option {
+	return func(p *Process) {
+		p.exitHistorySize = size
+	}
+}
+
+// recordExit appends a finished run to the exit history of a process, dropping the oldest exit if full.
+func (s *Supervisor) recordExit(name string, startedAt time.Time, err error, cleanupErr error) {
+	now := s.clock.Now()
+	record := ExitRecord{Time: now, Duration: now.Sub(startedAt), Err: err, CleanupErr: cleanupErr}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		record.Panic = panicErr.Value
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	process, exists := s.processes[name]
+	if !exists || process.exitHistorySize <= 0 {
+		return
+	}
+
+	// Copy instead of appending in place; snapshots share the backing array
+	start := max(0, len(process.exits)-process.exitHistorySize+1)
+	exits := make([]ExitRecord, 0, process.exitHistorySize)
+	exits = append(exits, process.exits[start:]...)
+	process.exits = append(exits, record)
+	s.processes[name] = process
+}
+
+// markRestarted records that a restart followed the last exit of a process.
+func (s *Supervisor) markRestarted(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	process, exists := s.processes[name]
+	if !exists || len(process.exits) == 0 {
+		return
+	}
+
+	exits := append([]ExitRecord(nil), process.exits...)
+	exits[len(exits)-1].Restarted = true
+	process.exits = exits
+	s.processes[name] = process
+}
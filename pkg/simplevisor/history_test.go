@@ -0,0 +1,104 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_ExitHistory(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	errFailed := errors.New("connection refused")
+	var runs atomic.Int32
+	s.Register("flaky", func(ctx context.Context) error {
+		switch runs.Add(1) {
+		case 1:
+			return errFailed
+		case 2:
+			panic("boom")
+		default:
+			return nil
+		}
+	}, WithRestart(RestartOnFailure, 5, 10*time.Millisecond))
+	s.Run()
+	defer s.Shutdown()
+
+	for runs.Load() < 3 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	waitForStatus(t, s, "flaky", StatusStopped, time.Second)
+
+	info, err := s.GetProcessInfo("flaky")
+	if err != nil {
+		t.Fatalf("GetProcessInfo failed: %v", err)
+	}
+	if len(info.Exits) != 3 {
+		t.Fatalf("Expected 3 exits, got %d", len(info.Exits))
+	}
+
+	first, second, third := info.Exits[0], info.Exits[1], info.Exits[2]
+	if !errors.Is(first.Err, errFailed) || first.Panic != nil || !first.Restarted {
+		t.Errorf("Unexpected first exit: %+v", first)
+	}
+	if second.Panic != "boom" || !second.Restarted {
+		t.Errorf("Unexpected second exit: %+v", second)
+	}
+	var panicErr *PanicError
+	if !errors.As(second.Err, &panicErr) {
+		t.Errorf("Expected *PanicError for the panicked run, got %v", second.Err)
+	}
+	if third.Err != nil || third.Restarted {
+		t.Errorf("Unexpected last exit: %+v", third)
+	}
+
+	for i, exit := range info.Exits {
+		if exit.Time.IsZero() || exit.Duration < 0 {
+			t.Errorf("Expected time and duration for exit %d, got %+v", i, exit)
+		}
+	}
+}
+
+func TestSupervisor_ExitHistoryBounded(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var runs atomic.Int32
+	s.Register("flapping", func(ctx context.Context) error {
+		return errors.New("run " + string(rune('0'+runs.Add(1))))
+	}, WithRestart(RestartOnFailure, 5, 10*time.Millisecond), WithExitHistory(2))
+	s.Run()
+	defer s.Shutdown()
+
+	for runs.Load() < 5 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	waitForStatus(t, s, "flapping", StatusStopped, time.Second)
+
+	info, _ := s.GetProcessInfo("flapping")
+	if len(info.Exits) != 2 {
+		t.Fatalf("Expected 2 exits, got %d", len(info.Exits))
+	}
+	if info.Exits[0].Err.Error() != "run 4" || info.Exits[1].Err.Error() != "run 5" {
+		t.Errorf("Expected the last two exits, got %v and %v", info.Exits[0].Err, info.Exits[1].Err)
+	}
+	if info.Exits[1].Restarted {
+		t.Error("Expected no restart after the restart limit")
+	}
+}
+
+func TestSupervisor_ExitHistoryDisabled(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	s.Register("worker", func(ctx context.Context) error { return nil }, WithExitHistory(0))
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "worker", StatusStopped, time.Second)
+	time.Sleep(20 * time.Millisecond)
+
+	if info, _ := s.GetProcessInfo("worker"); len(info.Exits) != 0 {
+		t.Errorf("Expected no exit history, got %v", info.Exits)
+	}
+}
@@ -0,0 +1,236 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrDraining is the error of a job submitted, or of an action which would start processes,
+// while the supervisor drains for shutdown
+var ErrDraining = errors.New("supervisor is draining")
+
+// JobStatus represents the current state of a submitted job
+type JobStatus int
+
+const (
+	JobPending   JobStatus = iota // Job is submitted but not started yet
+	JobRunning                    // Job is running
+	JobRetrying                   // Job failed and waits for the next attempt
+	JobSucceeded                  // Job finished without error
+	JobFailed                     // Job failed and won't be retried
+)
+
+func (s JobStatus) String() string {
+	switch s {
+	case JobPending:
+		return "pending"
+	case JobRunning:
+		return "running"
+	case JobRetrying:
+		return "retrying"
+	case JobSucceeded:
+		return "succeeded"
+	case JobFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// JobHandle tracks a job submitted to the supervisor and holds its result.
+type JobHandle struct {
+	name     string
+	done     chan struct{}
+	lock     sync.Mutex
+	status   JobStatus
+	attempts int
+	err      error
+
+	startedAt time.Time // Start of the current attempt
+	goroutine uint64    // Id of the job goroutine
+}
+
+func newJobHandle(name string) *JobHandle {
+	return &JobHandle{
+		name:   name,
+		done:   make(chan struct{}),
+		status: JobPending,
+	}
+}
+
+// Name returns the name of the job.
+func (j *JobHandle) Name() string {
+	return j.name
+}
+
+// Wait blocks until the job finishes and returns its result.
+// If ctx is done first, ctx.Err() is returned and the job keeps running.
+func (j *JobHandle) Wait(ctx context.Context) error {
+	select {
+	case <-j.done:
+		j.lock.Lock()
+		defer j.lock.Unlock()
+
+		return j.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done returns a channel which is closed when the job finishes.
+func (j *JobHandle) Done() <-chan struct{} {
+	return j.done
+}
+
+// Status returns the current status of the job.
+func (j *JobHandle) Status() JobStatus {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.status
+}
+
+// Attempts returns the number of times the job has been started.
+func (j *JobHandle) Attempts() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.attempts
+}
+
+func (j *JobHandle) setStatus(status JobStatus, now time.Time) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.status = status
+	if status == JobRunning {
+		j.attempts++
+		j.startedAt = now
+	}
+}
+
+func (j *JobHandle) finish(err error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.err = err
+	j.status = JobSucceeded
+	if err != nil {
+		j.status = JobFailed
+	}
+	close(j.done)
+}
+
+// Submit runs handler exactly once under supervision and returns a handle to its result.
+// A failed job is retried according to its RestartPolicy: RestartAlways and RestartOnFailure
+// retry up to maxRestarts times, RestartNever (default) doesn't retry.
+// A successful run is never repeated.
+// WithMaxRuntime and WithStartupTimeout bound every attempt; an attempt exceeding them fails.
+// Graceful shutdown waits for in-flight jobs within the shutdown timeout.
+// The job fails right away with ErrDraining once shutdown started, or if its name is used by
+// a process or pool.
+func (s *Supervisor) Submit(name string, handler ProcessFunc, options ...Option) *JobHandle {
+	process := newProcess(name, handler, options...)
+	job := newJobHandle(name)
+
+	if err := s.shutDownCtx.Err(); err != nil {
+		job.finish(fmt.Errorf("supervisor is shut down: %w", err))
+		return job
+	}
+
+	s.lock.Lock()
+	_, isProcess := s.processes[name]
+	_, isPool := s.pools[name]
+	switch {
+	case isClosed(s.draining):
+		s.lock.Unlock()
+		job.finish(ErrDraining)
+		return job
+	case isProcess || isPool:
+		s.lock.Unlock()
+		job.finish(fmt.Errorf("job name %s is used by a process", name))
+		return job
+	}
+	s.jobs[job] = struct{}{}
+	// Under the lock, so shutdown either waits for the job or rejects it
+	s.processWg.Add(1)
+	s.lock.Unlock()
+
+	go s.executeJob(process, job)
+
+	return job
+}
+
+func (s *Supervisor) executeJob(process Process, job *JobHandle) {
+	defer s.processWg.Done()
+	defer func() {
+		s.lock.Lock()
+		delete(s.jobs, job)
+		s.lock.Unlock()
+	}()
+
+	job.lock.Lock()
+	job.goroutine = goroutineID()
+	job.lock.Unlock()
+
+	for {
+		// Stopped by the shutdown while chaos mode delays the start
+		if !s.delayStart(s.shutDownCtx, process) {
+			err := s.shutDownCtx.Err()
+			if err == nil {
+				err = ErrDraining
+			}
+			job.finish(err)
+			return
+		}
+
+		startedAt := s.clock.Now()
+		job.setStatus(JobRunning, startedAt)
+		run := runInfo{
+			name:      process.name,
+			attempt:   job.Attempts(),
+			startedAt: startedAt,
+		}
+		ctx, finish := s.limitRun(s.shutDownCtx, process, &run)
+		err := finish(s.runProcess(ctx, process, run))
+		if err == nil || !s.shouldRetryJob(process, job) {
+			s.logger.Info("job finished",
+				slog.String("process_name", process.name),
+				slog.Int("attempts", job.Attempts()),
+				slog.Bool("succeeded", err == nil))
+			job.finish(err)
+			return
+		}
+
+		job.setStatus(JobRetrying, s.clock.Now())
+		s.logger.Info("retrying job",
+			slog.String("process_name", process.name),
+			slog.Duration("delay", process.restartDelay),
+			slog.Int("attempts", job.Attempts()))
+		s.metrics.recordProcessRestarted(process.name, process.restartPolicy, job.Attempts())
+
+		// Wait for restart delay or shutdown signal
+		select {
+		case <-s.clock.After(process.restartDelay):
+		case <-s.shutDownCtx.Done():
+			job.finish(err)
+			return
+		case <-s.draining:
+			job.finish(err)
+			return
+		}
+	}
+}
+
+// shouldRetryJob reports whether a failed job has any attempts left.
+func (s *Supervisor) shouldRetryJob(process Process, job *JobHandle) bool {
+	if process.restartPolicy == RestartNever || s.shutDownCtx.Err() != nil || isClosed(s.draining) {
+		return false
+	}
+
+	return process.maxRestarts <= 0 || job.Attempts() <= process.maxRestarts
+}
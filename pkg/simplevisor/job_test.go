@@ -0,0 +1,274 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_SubmitSucceeds(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	job := s.Submit("migration", func(ctx context.Context) error {
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := job.Wait(ctx); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if job.Status() != JobSucceeded {
+		t.Errorf("Expected JobSucceeded, got %s", job.Status())
+	}
+
+	if job.Attempts() != 1 {
+		t.Errorf("Expected 1 attempt, got %d", job.Attempts())
+	}
+
+	s.Shutdown()
+}
+
+func TestSupervisor_SubmitRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           RestartPolicy
+		failures         int32
+		maxRestarts      int
+		expectedAttempts int
+		expectedStatus   JobStatus
+	}{
+		{
+			name:             "RestartNever doesn't retry",
+			policy:           RestartNever,
+			failures:         1,
+			maxRestarts:      3,
+			expectedAttempts: 1,
+			expectedStatus:   JobFailed,
+		},
+		{
+			name:             "RestartOnFailure retries until success",
+			policy:           RestartOnFailure,
+			failures:         2,
+			maxRestarts:      3,
+			expectedAttempts: 3,
+			expectedStatus:   JobSucceeded,
+		},
+		{
+			name:             "RestartAlways stops after max restarts",
+			policy:           RestartAlways,
+			failures:         10,
+			maxRestarts:      2,
+			expectedAttempts: 3,
+			expectedStatus:   JobFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := createTestSupervisor(time.Second)
+
+			var execCount atomic.Int32
+			job := s.Submit("backfill", func(ctx context.Context) error {
+				if execCount.Add(1) <= tt.failures {
+					return errors.New("backfill failed")
+				}
+				return nil
+			}, WithRestart(tt.policy, tt.maxRestarts, 10*time.Millisecond))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			err := job.Wait(ctx)
+			if tt.expectedStatus == JobSucceeded && err != nil {
+				t.Errorf("Expected nil error, got %v", err)
+			}
+			if tt.expectedStatus == JobFailed && err == nil {
+				t.Error("Expected an error")
+			}
+
+			if job.Status() != tt.expectedStatus {
+				t.Errorf("Expected %s, got %s", tt.expectedStatus, job.Status())
+			}
+
+			if job.Attempts() != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, job.Attempts())
+			}
+
+			s.Shutdown()
+		})
+	}
+}
+
+func TestSupervisor_SubmitPanic(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	job := s.Submit("panic-job", func(ctx context.Context) error {
+		panic("job panic")
+	})
+
+	err := job.Wait(context.Background())
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected *PanicError, got %v", err)
+	}
+
+	if panicErr.Value != "job panic" {
+		t.Errorf("Expected 'job panic', got %v", panicErr.Value)
+	}
+
+	s.Shutdown()
+}
+
+func TestSupervisor_ShutdownWaitsForJobs(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var finished atomic.Bool
+	job := s.Submit("slow-job", func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		finished.Store(true)
+		return nil
+	})
+
+	time.Sleep(10 * time.Millisecond)
+	s.Shutdown()
+
+	if !finished.Load() {
+		t.Error("Shutdown should wait for in-flight jobs")
+	}
+
+	if err := job.Wait(context.Background()); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+}
+
+func TestSupervisor_SubmitAfterShutdown(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	s.Shutdown()
+
+	job := s.Submit("late-job", func(ctx context.Context) error {
+		t.Error("Job should not run after shutdown")
+		return nil
+	})
+
+	if err := job.Wait(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if job.Attempts() != 0 {
+		t.Errorf("Expected 0 attempts, got %d", job.Attempts())
+	}
+}
+
+func TestSupervisor_SubmitRunLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     ProcessFunc
+		option      Option
+		expectedErr error
+	}{
+		{
+			name: "max runtime fails the attempt",
+			handler: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			option:      WithMaxRuntime(20 * time.Millisecond),
+			expectedErr: ErrMaxRuntimeExceeded,
+		},
+		{
+			name: "startup timeout fails the attempt",
+			handler: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			option:      WithStartupTimeout(20 * time.Millisecond),
+			expectedErr: ErrStartupTimeout,
+		},
+		{
+			name: "ready job isn't cancelled by its startup timeout",
+			handler: func(ctx context.Context) error {
+				Ready(ctx)
+				time.Sleep(50 * time.Millisecond)
+				return ctx.Err()
+			},
+			option: WithStartupTimeout(20 * time.Millisecond),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := createTestSupervisor(time.Second)
+			defer s.Shutdown()
+
+			job := s.Submit("job", tt.handler, tt.option)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			err := job.Wait(ctx)
+			if tt.expectedErr == nil && err != nil {
+				t.Errorf("Expected nil error, got %v", err)
+			}
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestSupervisor_SubmitWhileDraining(t *testing.T) {
+	s := New(time.Second, slog.New(slog.DiscardHandler), WithDrainPeriod(time.Second))
+
+	// The worker keeps the supervisor draining until its context is cancelled
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+	waitForStatus(t, s, "worker", StatusRunning, time.Second)
+
+	shutdown := make(chan struct{})
+	go func() {
+		s.Shutdown()
+		close(shutdown)
+	}()
+	for !isClosed(s.draining) {
+		time.Sleep(time.Millisecond)
+	}
+
+	var ran atomic.Bool
+	job := s.Submit("late", func(ctx context.Context) error {
+		ran.Store(true)
+		return nil
+	})
+
+	if err := job.Wait(context.Background()); !errors.Is(err, ErrDraining) {
+		t.Errorf("Expected ErrDraining, got %v", err)
+	}
+	<-shutdown
+	if ran.Load() {
+		t.Error("Expected job submitted while draining not to run")
+	}
+}
+
+func TestSupervisor_SubmitRejectsProcessName(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	defer s.Shutdown()
+
+	s.Register("worker", func(ctx context.Context) error { return nil })
+	s.RegisterPool("consumer", 1, func(ctx context.Context) error { return nil })
+
+	for _, name := range []string{"worker", "consumer"} {
+		job := s.Submit(name, func(ctx context.Context) error { return nil })
+		if err := job.Wait(context.Background()); err == nil {
+			t.Errorf("Expected error for job named like process %s", name)
+		}
+	}
+}
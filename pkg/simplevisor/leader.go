@@ -0,0 +1,197 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Locker acquires an exclusive lease which guards a leader-elected process.
+// Implementations must be safe for concurrent use.
+type Locker interface {
+	// Acquire blocks until the lease is held or ctx is done.
+	// ctx only bounds the wait; the lease must stay held after ctx is done, until it is released or lost.
+	Acquire(ctx context.Context) (Lease, error)
+}
+
+// Lease is a lock held by a single holder at a time.
+type Lease interface {
+	// Lost returns a channel which is closed when the lease is lost without being released.
+	Lost() <-chan struct{}
+	// Release gives up the lease.
+	Release() error
+}
+
+// WithLeaderElection runs the process only while it holds the lease of locker.
+// While waiting for the lease the process is in StatusStandby.
+// When the lease is lost the process context is cancelled and the process waits for the lease again;
+// such a run doesn't count against the restart budget.
+func WithLeaderElection(locker Locker) Option {
+	return func(p *Process) {
+		p.locker = locker
+	}
+}
+
+// acquireLease blocks until the process holds its lease.
+// It returns a nil lease for processes without leader election and false if ctx is done
+// or the supervisor starts draining.
+func (s *Supervisor) acquireLease(ctx context.Context, name string, process Process) (Lease, bool) {
+	if process.locker == nil {
+		return nil, true
+	}
+
+	ctx, cancel := s.untilDraining(ctx)
+	defer cancel()
+
+	s.setProcessStatus(name, StatusStandby)
+	s.logger.Info("waiting for leadership", slog.String("process_name", name))
+
+	for {
+		lease, err := process.locker.Acquire(ctx)
+		if err == nil {
+			s.logger.Info("acquired leadership", slog.String("process_name", name))
+			s.publish(Event{Type: EventLeadershipAcquired, Process: name})
+			return lease, true
+		}
+
+		if ctx.Err() != nil {
+			return nil, false
+		}
+
+		s.logger.Error("failed to acquire leadership",
+			slog.String("process_name", name),
+			slog.String("error", err.Error()))
+
+		select {
+		case <-s.clock.After(process.restartDelay):
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// leaseContext returns a context which is cancelled as soon as the lease is lost.
+func leaseContext(ctx context.Context, lease Lease) (context.Context, context.CancelFunc) {
+	if lease == nil {
+		return ctx, func() {}
+	}
+
+	leaseCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-lease.Lost():
+			cancel()
+		case <-leaseCtx.Done():
+		}
+	}()
+
+	return leaseCtx, cancel
+}
+
+// releaseLease releases the lease after a run and reports whether it was lost during the run.
+func (s *Supervisor) releaseLease(name string, lease Lease) bool {
+	if lease == nil {
+		return false
+	}
+
+	lost := false
+	select {
+	case <-lease.Lost():
+		lost = true
+		s.logger.Warn("lost leadership", slog.String("process_name", name))
+		s.publish(Event{Type: EventLeadershipLost, Process: name})
+	default:
+	}
+
+	if err := lease.Release(); err != nil {
+		s.logger.Error("failed to release leadership",
+			slog.String("process_name", name),
+			slog.String("error", err.Error()))
+	}
+
+	return lost
+}
+
+// MemoryLocker is an in-process Locker, mainly for tests.
+// Share one MemoryLocker between supervisors to let them compete for the same lease.
+type MemoryLocker struct {
+	lock   sync.Mutex
+	holder *memoryLease
+	free   chan struct{} // Closed when the current holder leaves
+}
+
+// NewMemoryLocker returns a new MemoryLocker.
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{}
+}
+
+// Acquire blocks until the lease is free or ctx is done.
+func (m *MemoryLocker) Acquire(ctx context.Context) (Lease, error) {
+	for {
+		m.lock.Lock()
+		if m.holder == nil {
+			lease := &memoryLease{locker: m, lost: make(chan struct{})}
+			m.holder = lease
+			m.free = make(chan struct{})
+			m.lock.Unlock()
+
+			return lease, nil
+		}
+		free := m.free
+		m.lock.Unlock()
+
+		select {
+		case <-free:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Revoke takes the lease away from its current holder, as if it expired.
+func (m *MemoryLocker) Revoke() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.holder == nil {
+		return
+	}
+
+	m.holder.lostOnce.Do(func() { close(m.holder.lost) })
+	m.leaveLocked()
+}
+
+// Held reports whether the lease is currently held.
+func (m *MemoryLocker) Held() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.holder != nil
+}
+
+// leaveLocked frees the lease. The caller must hold m.lock.
+func (m *MemoryLocker) leaveLocked() {
+	m.holder = nil
+	close(m.free)
+}
+
+type memoryLease struct {
+	locker   *MemoryLocker
+	lost     chan struct{}
+	lostOnce sync.Once
+}
+
+func (l *memoryLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *memoryLease) Release() error {
+	l.locker.lock.Lock()
+	defer l.locker.lock.Unlock()
+
+	if l.locker.holder == l {
+		l.locker.leaveLocked()
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+//go:build unix
+
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultFileLockRetryInterval is the interval FileLocker retries a busy lock
+const DefaultFileLockRetryInterval = 1 * time.Second
+
+// FileLocker is a Locker backed by flock(2) on a local file.
+// It elects a leader among processes sharing the same host or volume.
+type FileLocker struct {
+	path          string
+	retryInterval time.Duration
+}
+
+// NewFileLocker returns a FileLocker for the lock file at path, creating the file if needed.
+func NewFileLocker(path string, retryInterval time.Duration) *FileLocker {
+	if retryInterval <= 0 {
+		retryInterval = DefaultFileLockRetryInterval
+	}
+
+	return &FileLocker{
+		path:          filepath.Clean(path),
+		retryInterval: retryInterval,
+	}
+}
+
+// Acquire blocks until the file lock is held or ctx is done.
+func (f *FileLocker) Acquire(ctx context.Context) (Lease, error) {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %q: %w", f.path, err)
+	}
+
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB) // #nosec G115 -- fd fits in int
+		if err == nil {
+			return &fileLease{file: file, lost: make(chan struct{})}, nil
+		}
+
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to lock %q: %w", f.path, err)
+		}
+
+		select {
+		case <-time.After(f.retryInterval):
+		case <-ctx.Done():
+			_ = file.Close()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// fileLease holds the flock until released. A local flock can't be lost while the file is open.
+type fileLease struct {
+	file        *os.File
+	lost        chan struct{}
+	releaseOnce sync.Once
+	releaseErr  error
+}
+
+func (l *fileLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *fileLease) Release() error {
+	l.releaseOnce.Do(func() {
+		// Closing the file releases the lock
+		l.releaseErr = l.file.Close()
+	})
+
+	return l.releaseErr
+}
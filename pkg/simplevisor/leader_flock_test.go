@@ -0,0 +1,38 @@
+//go:build unix
+
+package simplevisor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLocker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduler.lock")
+	first := NewFileLocker(path, 10*time.Millisecond)
+	second := NewFileLocker(path, 10*time.Millisecond)
+
+	lease, err := first.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := second.Acquire(ctx); err == nil {
+		t.Fatal("Second locker should not acquire a held lock")
+	}
+
+	if err := lease.Release(); err != nil {
+		t.Fatalf("Failed to release lock: %v", err)
+	}
+
+	lease, err = second.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Second locker should acquire a released lock: %v", err)
+	}
+	_ = lease.Release()
+}
@@ -0,0 +1,81 @@
+package simplevisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_LeaderElection(t *testing.T) {
+	locker := NewMemoryLocker()
+
+	var runs atomic.Int32
+	handler := func(ctx context.Context) error {
+		runs.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	first := createTestSupervisor(time.Second)
+	first.Register("scheduler", handler, WithLeaderElection(locker))
+	first.Run()
+	waitForStatus(t, first, "scheduler", StatusRunning, time.Second)
+
+	second := createTestSupervisor(time.Second)
+	second.Register("scheduler", handler, WithLeaderElection(locker))
+	second.Run()
+	waitForStatus(t, second, "scheduler", StatusStandby, time.Second)
+
+	if runs.Load() != 1 {
+		t.Fatalf("Only the leader should run, got %d runs", runs.Load())
+	}
+
+	// Losing the lease cancels the leader and lets the other one take over
+	locker.Revoke()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && runs.Load() < 2 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if runs.Load() < 2 {
+		t.Fatal("Another replica should take over the lost lease")
+	}
+
+	first.Shutdown()
+	second.Shutdown()
+
+	if locker.Held() {
+		t.Error("Lease should be released after shutdown")
+	}
+
+	info, _ := first.GetProcessInfo("scheduler")
+	if info.RestartCount != 0 {
+		t.Errorf("Losing the lease shouldn't count as restart, got %d", info.RestartCount)
+	}
+}
+
+func TestMemoryLocker_AcquireCancelled(t *testing.T) {
+	locker := NewMemoryLocker()
+
+	lease, err := locker.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to acquire lease: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := locker.Acquire(ctx); err == nil {
+		t.Error("Acquire should fail while the lease is held")
+	}
+
+	if err := lease.Release(); err != nil {
+		t.Fatalf("Failed to release lease: %v", err)
+	}
+
+	if _, err := locker.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire should succeed after release, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrMaxRuntimeExceeded is the error of a run which was recycled after its max runtime, see WithMaxRuntime.
+	ErrMaxRuntimeExceeded = errors.New("max runtime exceeded")
+	// ErrStartupTimeout is the error of a run which didn't signal readiness in time, see WithStartupTimeout.
+	ErrStartupTimeout = errors.New("startup timeout exceeded")
+)
+
+// WithMaxRuntime cancels the context of every run of the process after d, e.g. to recycle
+// a worker which leaks memory. The run fails with ErrMaxRuntimeExceeded and is restarted
+// under the RestartPolicy of the process without using up its restart budget.
+func WithMaxRuntime(d time.Duration) Option {
+	return func(p *Process) {
+		p.maxRuntime = d
+	}
+}
+
+// WithStartupTimeout cancels a run of the process which doesn't call Ready within d.
+// The run fails with ErrStartupTimeout and is handled like any failed run under the RestartPolicy.
+func WithStartupTimeout(d time.Duration) Option {
+	return func(p *Process) {
+		p.startupTimeout = d
+	}
+}
+
+// Ready signals that the process finished starting up, see WithStartupTimeout.
+// Calling it more than once or outside a process has no effect.
+func Ready(ctx context.Context) {
+	if run, ok := runInfoFrom(ctx); ok && run.ready != nil {
+		run.ready()
+	}
+}
+
+// limitRun applies the max runtime and startup timeout of a process to a run and sets its readiness signal.
+// The returned function must be called with the error of the run when it finishes; it returns
+// ErrMaxRuntimeExceeded or ErrStartupTimeout in place of the error if the run was cancelled by a limit.
+func (s *Supervisor) limitRun(ctx context.Context, process Process, run *runInfo) (context.Context, func(error) error) {
+	name := run.name
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	var timers []Timer
+	if process.maxRuntime > 0 {
+		timers = append(timers, s.clock.AfterFunc(process.maxRuntime, func() {
+			s.logger.Info("process reached max runtime, recycling it",
+				slog.String("process_name", name),
+				slog.Duration("max_runtime", process.maxRuntime))
+			cancel(ErrMaxRuntimeExceeded)
+		}))
+	}
+
+	var startup Timer
+	if process.startupTimeout > 0 {
+		startup = s.clock.AfterFunc(process.startupTimeout, func() {
+			s.logger.Error("process did not become ready within startup timeout",
+				slog.String("process_name", name),
+				slog.Duration("startup_timeout", process.startupTimeout))
+			cancel(ErrStartupTimeout)
+		})
+		timers = append(timers, startup)
+	}
+
+	var readyOnce sync.Once
+	run.ready = func() {
+		readyOnce.Do(func() {
+			if startup != nil && !startup.Stop() {
+				return // Too late, the run is being cancelled
+			}
+
+			s.setReady(name, true)
+			s.logger.Info("process is ready", slog.String("process_name", name))
+			s.publish(Event{Type: EventReady, Process: name})
+		})
+	}
+
+	return ctx, func(err error) error {
+		for _, timer := range timers {
+			timer.Stop()
+		}
+
+		cause := context.Cause(ctx)
+		cancel(nil)
+		s.setReady(name, false)
+
+		switch {
+		case errors.Is(cause, ErrMaxRuntimeExceeded), errors.Is(cause, ErrStartupTimeout):
+			return fmt.Errorf("process %s: %w", name, cause)
+		default:
+			return err
+		}
+	}
+}
+
+// setReady records whether the current run of a process signalled readiness
+func (s *Supervisor) setReady(name string, ready bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if process, exists := s.processes[name]; exists {
+		process.ready = ready
+		s.processes[name] = process
+	}
+}
@@ -0,0 +1,80 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_MaxRuntime(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var runs atomic.Int32
+	s.Register("leaky", func(ctx context.Context) error {
+		runs.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithRestart(RestartOnFailure, 1, 10*time.Millisecond), WithMaxRuntime(30*time.Millisecond))
+	s.Run()
+	defer s.Shutdown()
+
+	// With a budget of a single restart, recycling must not count against it
+	for runs.Load() < 4 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	info, _ := s.GetProcessInfo("leaky")
+	if info.RestartCount != 0 {
+		t.Errorf("Expected recycled runs to keep the restart budget, got restart count %d", info.RestartCount)
+	}
+	if len(info.Exits) == 0 || !errors.Is(info.Exits[0].Err, ErrMaxRuntimeExceeded) {
+		t.Errorf("Expected ErrMaxRuntimeExceeded in exit history, got %v", info.Exits)
+	}
+}
+
+func TestSupervisor_StartupTimeout(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var runs atomic.Int32
+	s.Register("slow-start", func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			// Never becomes ready and returns nil once cancelled
+			<-ctx.Done()
+			return nil
+		}
+
+		Ready(ctx)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithRestart(RestartOnFailure, 3, 10*time.Millisecond), WithStartupTimeout(30*time.Millisecond))
+	s.Run()
+	defer s.Shutdown()
+
+	for runs.Load() < 2 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// The second run becomes ready and must not be cancelled
+	time.Sleep(60 * time.Millisecond)
+
+	info, _ := s.GetProcessInfo("slow-start")
+	if info.Status != StatusRunning || !info.Ready {
+		t.Errorf("Expected ready running process, got status %v ready %t", info.Status, info.Ready)
+	}
+	if info.Attempts != 2 {
+		t.Errorf("Expected 2 runs, got %d", info.Attempts)
+	}
+	if info.RestartCount != 1 {
+		t.Errorf("Expected the timed out run to count as a failure, got restart count %d", info.RestartCount)
+	}
+	if len(info.Exits) != 1 || !errors.Is(info.Exits[0].Err, ErrStartupTimeout) {
+		t.Errorf("Expected ErrStartupTimeout in exit history, got %v", info.Exits)
+	}
+}
+
+func TestReady_OutsideProcess(t *testing.T) {
+	// Must not panic
+	Ready(context.Background())
+}
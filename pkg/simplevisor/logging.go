@@ -0,0 +1,185 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// WithLogDedup collapses identical supervisor log records about a process, e.g. of a crash loop.
+// The first record is logged; identical records within window are counted and logged as a single
+// summary once the window is over. Records are identical if they have the same message, process and error.
+func WithLogDedup(window time.Duration) SupervisorOption {
+	return func(s *Supervisor) {
+		s.logDedupWindow = window
+	}
+}
+
+// WithProcessLogLevel sets the minimum log level of a process, for both its own logs, see Logger,
+// and the supervisor logs about it. It overrides the level of the logger passed to New.
+func WithProcessLogLevel(name string, level slog.Level) SupervisorOption {
+	return func(s *Supervisor) {
+		if s.logLevels == nil {
+			s.logLevels = make(map[string]slog.Level)
+		}
+		s.logLevels[name] = level
+	}
+}
+
+// setupLogging wraps the loggers of the supervisor for WithLogDedup and WithProcessLogLevel.
+func (s *Supervisor) setupLogging(sLog *slog.Logger) {
+	if s.logDedupWindow <= 0 && len(s.logLevels) == 0 {
+		return
+	}
+
+	state := &logState{
+		clock:   s.clock,
+		window:  s.logDedupWindow,
+		levels:  s.logLevels,
+		repeats: make(map[logKey]*logRepeat),
+	}
+	first := true
+	for _, level := range s.logLevels {
+		if first || level < state.minLevel {
+			state.minLevel = level
+			first = false
+		}
+	}
+
+	s.logger = slog.New(&logHandler{inner: sLog.Handler(), state: state, dedup: true}).WithGroup(LogNSSupervisor)
+	s.processLogger = slog.New(&logHandler{inner: sLog.Handler(), state: state})
+}
+
+// logHandler applies per-process log levels and deduplication on top of another handler.
+type logHandler struct {
+	inner   slog.Handler
+	state   *logState
+	process string // Set by WithAttrs for process loggers
+	dedup   bool
+}
+
+// logState is shared by a logHandler and the handlers derived from it.
+type logState struct {
+	clock    Clock
+	window   time.Duration
+	levels   map[string]slog.Level
+	minLevel slog.Level // Lowest level override
+
+	lock    sync.Mutex
+	repeats map[logKey]*logRepeat
+}
+
+type logKey struct {
+	process string
+	message string
+	err     string
+}
+
+// logRepeat counts the suppressed repeats of a record within the dedup window.
+type logRepeat struct {
+	count   int
+	level   slog.Level
+	handler slog.Handler // Logs the summary with the groups of the first record
+}
+
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.process != "" {
+		if min, ok := h.state.levels[h.process]; ok {
+			return level >= min
+		}
+	}
+
+	// The process of a record is only known in Handle
+	return h.inner.Enabled(ctx, level) || (len(h.state.levels) > 0 && level >= h.state.minLevel)
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	process, errText := h.process, ""
+	r.Attrs(func(a slog.Attr) bool {
+		switch a.Key {
+		case "process_name":
+			process = a.Value.String()
+		case "error", "panic":
+			errText = a.Value.String()
+		}
+		return true
+	})
+
+	if min, ok := h.state.levels[process]; ok {
+		if r.Level < min {
+			return nil
+		}
+	} else if !h.inner.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	if h.dedup && process != "" && h.state.window > 0 &&
+		h.state.suppress(logKey{process: process, message: r.Message, err: errText}, r.Level, h.inner) {
+		return nil
+	}
+
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.inner = h.inner.WithAttrs(attrs)
+	for _, a := range attrs {
+		if a.Key == "process_name" {
+			c.process = a.Value.String()
+		}
+	}
+
+	return &c
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.inner = h.inner.WithGroup(name)
+
+	return &c
+}
+
+// suppress reports whether a record is a repeat within the dedup window and counts it.
+// The first record of a window starts the timer of its summary.
+func (st *logState) suppress(key logKey, level slog.Level, handler slog.Handler) bool {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	if repeat, ok := st.repeats[key]; ok {
+		repeat.count++
+		return true
+	}
+
+	st.repeats[key] = &logRepeat{level: level, handler: handler}
+	st.clock.AfterFunc(st.window, func() {
+		st.summarize(key)
+	})
+
+	return false
+}
+
+// summarize ends the dedup window of a record and logs how often it was suppressed.
+func (st *logState) summarize(key logKey) {
+	st.lock.Lock()
+	repeat := st.repeats[key]
+	delete(st.repeats, key)
+	st.lock.Unlock()
+
+	if repeat == nil || repeat.count == 0 {
+		return
+	}
+
+	r := slog.NewRecord(st.clock.Now(), repeat.level, "repeated log records suppressed", 0)
+	r.AddAttrs(
+		slog.String("process_name", key.process),
+		slog.String("message", key.message),
+		slog.Int("count", repeat.count),
+		slog.Duration("window", st.window))
+	if key.err != "" {
+		r.AddAttrs(slog.String("error", key.err))
+	}
+
+	_ = repeat.handler.Handle(context.Background(), r)
+}
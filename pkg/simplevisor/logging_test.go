@@ -0,0 +1,94 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestWithLogDedup(t *testing.T) {
+	buf := &syncBuffer{}
+	s := New(time.Second, slog.New(slog.NewJSONHandler(buf, nil)), WithLogDedup(100*time.Millisecond))
+
+	err := errors.New("connection refused")
+	for range 3 {
+		s.logger.Error("process failed", slog.String("process_name", "a"), slog.String("error", err.Error()))
+	}
+	s.logger.Error("process failed", slog.String("process_name", "a"), slog.String("error", "timeout"))
+	s.logger.Error("process failed", slog.String("process_name", "b"), slog.String("error", err.Error()))
+	s.logger.Info("supervisor started")
+	s.logger.Info("supervisor started")
+
+	if got := len(buf.messages(t)); got != 5 {
+		t.Fatalf("expected 5 records before the window is over, got %d: %v", got, buf.messages(t))
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	summaries := buf.records(t, "repeated log records suppressed")
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d: %v", len(summaries), buf.messages(t))
+	}
+
+	summary := summaries[0]["supervisor"].(map[string]any)
+	if summary["process_name"] != "a" || summary["message"] != "process failed" ||
+		summary["count"] != float64(2) || summary["error"] != "connection refused" {
+		t.Errorf("unexpected summary: %v", summary)
+	}
+	if summaries[0]["level"] != "ERROR" {
+		t.Errorf("expected summary at level of the record, got %v", summaries[0]["level"])
+	}
+
+	// A new window starts after the summary
+	s.logger.Error("process failed", slog.String("process_name", "a"), slog.String("error", err.Error()))
+	if got := len(buf.messages(t)); got != 7 {
+		t.Errorf("expected record to be logged in a new window, got %d records", got)
+	}
+}
+
+func TestWithProcessLogLevel(t *testing.T) {
+	buf := &syncBuffer{}
+	s := New(time.Second, slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithProcessLogLevel("verbose", slog.LevelDebug),
+		WithProcessLogLevel("quiet", slog.LevelError))
+
+	handler := func(ctx context.Context) error {
+		Logger(ctx).Debug("debug message")
+		Logger(ctx).Warn("warn message")
+		<-ctx.Done()
+		return nil
+	}
+	s.Register("verbose", handler)
+	s.Register("quiet", handler)
+	s.Register("default", handler)
+	s.Run()
+
+	waitForStatus(t, s, "verbose", StatusRunning, time.Second)
+	waitForStatus(t, s, "quiet", StatusRunning, time.Second)
+	waitForStatus(t, s, "default", StatusRunning, time.Second)
+	time.Sleep(50 * time.Millisecond)
+	s.Shutdown()
+
+	count := make(map[string]int)
+	for _, record := range buf.allRecords(t) {
+		name, ok := record["process_name"].(string)
+		if !ok {
+			if group, isGroup := record["supervisor"].(map[string]any); isGroup {
+				name, _ = group["process_name"].(string)
+			}
+		}
+		count[name]++
+	}
+
+	if count["verbose"] < 3 {
+		t.Errorf("expected debug and supervisor logs of verbose process, got %d records", count["verbose"])
+	}
+	if count["quiet"] != 0 {
+		t.Errorf("expected no logs of quiet process, got %d records", count["quiet"])
+	}
+	if count["default"] != 0 {
+		t.Errorf("expected default process to keep the level of the logger, got %d records", count["default"])
+	}
+}
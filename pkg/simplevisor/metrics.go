@@ -2,6 +2,8 @@ package simplevisor
 
 import (
 	"context"
+	"sort"
+	"sync"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -17,6 +19,11 @@ type metricsRecorder interface {
 	recordRestartLimitExceeded(name string, maxRestarts int)
 	recordShutdownTimeout()
 	updateTotalProcesses(count int, status ProcessStatus)
+	updatePoolReplicas(pool string, size int, running int)
+	recordBreakerState(name string, state BreakerState)
+	setProcessLabels(name string, labels map[string]string)
+	recordChaosFault(name string, fault string)
+	recordGoroutineLeak(name string, count int)
 }
 
 // Metrics holds all OpenTelemetry metrics for the supervisor
@@ -38,6 +45,24 @@ type Metrics struct {
 
 	// Performance metrics
 	shutdownTimeouts metric.Int64Counter
+
+	// Pool metrics
+	poolSize            metric.Int64Gauge
+	poolReplicasRunning metric.Int64Gauge
+
+	// Circuit breaker metrics
+	breakerState  metric.Int64Gauge
+	breakerOpened metric.Int64Counter
+
+	// Chaos metrics
+	chaosFaults metric.Int64Counter
+
+	// Goroutine metrics
+	goroutineLeaks metric.Int64Counter
+
+	// Process labels attached to the metrics of a process, by process name
+	labelsLock sync.RWMutex
+	labels     map[string][]attribute.KeyValue
 }
 
 // newMetrics creates and initializes all metrics
@@ -75,7 +100,7 @@ func newMetrics() (*Metrics, error) {
 
 	m.processStatusGauge, err = meter.Int64Gauge(
 		"simplevisor_process_status",
-		metric.WithDescription("Process status (1=running, 0=stopped, -1=restarting)"),
+		metric.WithDescription("Process status (1=running, 0=stopped, -1=restarting, 2=standby, 3=paused)"),
 	)
 	if err != nil {
 		return nil, err
@@ -131,6 +156,58 @@ func newMetrics() (*Metrics, error) {
 		return nil, err
 	}
 
+	// Pool metrics
+	m.poolSize, err = meter.Int64Gauge(
+		"simplevisor_pool_size",
+		metric.WithDescription("Desired number of replicas for each pool"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.poolReplicasRunning, err = meter.Int64Gauge(
+		"simplevisor_pool_replicas_running",
+		metric.WithDescription("Number of running replicas for each pool"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Circuit breaker metrics
+	m.breakerState, err = meter.Int64Gauge(
+		"simplevisor_process_breaker_state",
+		metric.WithDescription("Circuit breaker state (0=closed, 1=open, 2=half_open)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.breakerOpened, err = meter.Int64Counter(
+		"simplevisor_process_breaker_opened_total",
+		metric.WithDescription("Total number of times a process circuit breaker opened"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chaos metrics
+	m.chaosFaults, err = meter.Int64Counter(
+		"simplevisor_chaos_faults_total",
+		metric.WithDescription("Total number of faults injected by chaos mode, by fault"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Goroutine metrics
+	m.goroutineLeaks, err = meter.Int64Counter(
+		"simplevisor_goroutine_leaks_total",
+		metric.WithDescription("Total number of goroutines still alive after their process run returned"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return m, nil
 }
 
@@ -140,10 +217,7 @@ func (m *Metrics) recordProcessStarted(ctx context.Context, name string, policy
 		return
 	}
 
-	attrs := []attribute.KeyValue{
-		attribute.String("process_name", name),
-		attribute.String("restart_policy", policy.String()),
-	}
+	attrs := m.processAttrs(name, attribute.String("restart_policy", policy.String()))
 
 	m.processStarted.Add(ctx, 1, metric.WithAttributes(attrs...))
 	m.processesRunning.Add(ctx, 1, metric.WithAttributes(attrs...))
@@ -156,14 +230,11 @@ func (m *Metrics) recordProcessStopped(name string, reason string) {
 		return
 	}
 
-	attrs := []attribute.KeyValue{
-		attribute.String("process_name", name),
-		attribute.String("reason", reason), // error, success, manual, shutdown
-	}
+	attrs := m.processAttrs(name, attribute.String("reason", reason)) // error, success, manual, shutdown
 
 	m.processStopped.Add(context.Background(), 1, metric.WithAttributes(attrs...))
 	m.processesRunning.Add(context.Background(), -1,
-		metric.WithAttributes(attribute.String("process_name", name)))
+		metric.WithAttributes(m.processAttrs(name)...))
 	m.updateProcessStatus(name, StatusStopped)
 }
 
@@ -173,10 +244,7 @@ func (m *Metrics) recordProcessRestarted(name string, policy RestartPolicy, rest
 		return
 	}
 
-	attrs := []attribute.KeyValue{
-		attribute.String("process_name", name),
-		attribute.String("restart_policy", policy.String()),
-	}
+	attrs := m.processAttrs(name, attribute.String("restart_policy", policy.String()))
 
 	m.processRestarted.Add(context.Background(), 1, metric.WithAttributes(attrs...))
 	m.updateRestartCount(name, restartCount)
@@ -189,9 +257,7 @@ func (m *Metrics) recordProcessPanic(name string) {
 		return
 	}
 
-	attrs := []attribute.KeyValue{
-		attribute.String("process_name", name),
-	}
+	attrs := m.processAttrs(name)
 
 	m.processPanics.Add(context.Background(), 1, metric.WithAttributes(attrs...))
 }
@@ -202,10 +268,7 @@ func (m *Metrics) recordRestartLimitExceeded(name string, maxRestarts int) {
 		return
 	}
 
-	attrs := []attribute.KeyValue{
-		attribute.String("process_name", name),
-		attribute.Int("max_restarts", maxRestarts),
-	}
+	attrs := m.processAttrs(name, attribute.Int("max_restarts", maxRestarts))
 
 	m.restartLimitExceeded.Add(context.Background(), 1, metric.WithAttributes(attrs...))
 }
@@ -225,10 +288,7 @@ func (m *Metrics) updateProcessStatus(name string, status ProcessStatus) {
 		return
 	}
 
-	attrs := []attribute.KeyValue{
-		attribute.String("process_name", name),
-		attribute.String("status", status.String()),
-	}
+	attrs := m.processAttrs(name, attribute.String("status", status.String()))
 
 	var value int64
 	switch status {
@@ -238,6 +298,10 @@ func (m *Metrics) updateProcessStatus(name string, status ProcessStatus) {
 		value = 0
 	case StatusRestarting:
 		value = -1
+	case StatusStandby:
+		value = 2
+	case StatusPaused:
+		value = 3
 	}
 
 	// Gauges record absolute values, no need to reset
@@ -250,9 +314,7 @@ func (m *Metrics) updateRestartCount(name string, count int) {
 		return
 	}
 
-	attrs := []attribute.KeyValue{
-		attribute.String("process_name", name),
-	}
+	attrs := m.processAttrs(name)
 
 	// Gauges record absolute values - set the current restart count
 	m.processRestartCount.Record(context.Background(), int64(count),
@@ -273,6 +335,95 @@ func (m *Metrics) updateTotalProcesses(count int, status ProcessStatus) {
 		metric.WithAttributes(attrs...))
 }
 
+// updatePoolReplicas updates the size and running replicas gauges for a pool
+func (m *Metrics) updatePoolReplicas(pool string, size int, running int) {
+	if m == nil {
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("pool_name", pool),
+	}
+
+	m.poolSize.Record(context.Background(), int64(size), metric.WithAttributes(attrs...))
+	m.poolReplicasRunning.Record(context.Background(), int64(running), metric.WithAttributes(attrs...))
+}
+
+// recordBreakerState records a circuit breaker state change
+func (m *Metrics) recordBreakerState(name string, state BreakerState) {
+	if m == nil {
+		return
+	}
+
+	attrs := m.processAttrs(name)
+
+	m.breakerState.Record(context.Background(), int64(state), metric.WithAttributes(attrs...))
+	if state == BreakerOpen {
+		m.breakerOpened.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+	}
+}
+
+// recordChaosFault records a fault injected by chaos mode
+func (m *Metrics) recordChaosFault(name string, fault string) {
+	if m == nil {
+		return
+	}
+
+	attrs := m.processAttrs(name, attribute.String("fault", fault))
+
+	m.chaosFaults.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+}
+
+// recordGoroutineLeak records goroutines of a finished process run which are still alive
+func (m *Metrics) recordGoroutineLeak(name string, count int) {
+	if m == nil {
+		return
+	}
+
+	m.goroutineLeaks.Add(context.Background(), int64(count), metric.WithAttributes(m.processAttrs(name)...))
+}
+
+// setProcessLabels sets the labels attached to the metrics of a process; nil labels remove them
+func (m *Metrics) setProcessLabels(name string, labels map[string]string) {
+	if m == nil {
+		return
+	}
+
+	m.labelsLock.Lock()
+	defer m.labelsLock.Unlock()
+
+	if len(labels) == 0 {
+		delete(m.labels, name)
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(labels))
+	for key, value := range labels {
+		attrs = append(attrs, attribute.String("label_"+key, value))
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+
+	if m.labels == nil {
+		m.labels = make(map[string][]attribute.KeyValue)
+	}
+	m.labels[name] = attrs
+}
+
+// processAttrs returns the attributes of a process metric: its name, extra attributes and its labels
+func (m *Metrics) processAttrs(name string, extra ...attribute.KeyValue) []attribute.KeyValue {
+	m.labelsLock.RLock()
+	defer m.labelsLock.RUnlock()
+
+	attrs := make([]attribute.KeyValue, 0, 1+len(extra)+len(m.labels[name]))
+	attrs = append(attrs, attribute.String("process_name", name))
+	attrs = append(attrs, extra...)
+	attrs = append(attrs, m.labels[name]...)
+
+	return attrs
+}
+
 // String methods for enums to provide readable metric labels
 func (r RestartPolicy) String() string {
 	switch r {
@@ -295,6 +446,10 @@ func (s ProcessStatus) String() string {
 		return "running"
 	case StatusRestarting:
 		return "restarting"
+	case StatusStandby:
+		return "standby"
+	case StatusPaused:
+		return "paused"
 	default:
 		return "unknown"
 	}
@@ -311,3 +466,8 @@ func (n *noOpMetrics) recordProcessPanic(name string)
 func (n *noOpMetrics) recordRestartLimitExceeded(name string, maxRestarts int)                     {}
 func (n *noOpMetrics) recordShutdownTimeout()                                                      {}
 func (n *noOpMetrics) updateTotalProcesses(count int, status ProcessStatus)                        {}
+func (n *noOpMetrics) updatePoolReplicas(pool string, size int, running int)                       {}
+func (n *noOpMetrics) recordBreakerState(name string, state BreakerState)                          {}
+func (n *noOpMetrics) setProcessLabels(name string, labels map[string]string)                      {}
+func (n *noOpMetrics) recordChaosFault(name string, fault string)                                  {}
+func (n *noOpMetrics) recordGoroutineLeak(name string, count int)                                  {}
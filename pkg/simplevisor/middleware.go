@@ -0,0 +1,127 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+)
+
+// Middleware wraps every run of a process with cross-cutting behaviour such as timing,
+// tracing or resource acquisition. It must call next to run the process.
+type Middleware func(next ProcessFunc) ProcessFunc
+
+// WithMiddleware wraps the runs of the process with middlewares, inside the supervisor middlewares.
+// The first middleware is the outermost one.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(p *Process) {
+		p.middlewares = append(p.middlewares, middlewares...)
+	}
+}
+
+// Use appends middlewares to the supervisor chain which wraps the runs of every process and job.
+// The chain starts with RecoverMiddleware, LoggingMiddleware and MetricsMiddleware;
+// use SetMiddleware to reorder or replace them.
+func (s *Supervisor) Use(middlewares ...Middleware) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.middlewares = append(s.middlewares, middlewares...)
+}
+
+// SetMiddleware replaces the supervisor chain, including the built-in middlewares.
+// The first middleware is the outermost one.
+// A panic which isn't recovered by the chain is still recovered by the supervisor and returned as a *PanicError.
+func (s *Supervisor) SetMiddleware(middlewares ...Middleware) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.middlewares = append([]Middleware(nil), middlewares...)
+}
+
+// defaultMiddlewares returns the built-in supervisor chain.
+func (s *Supervisor) defaultMiddlewares() []Middleware {
+	return []Middleware{s.RecoverMiddleware(), s.LoggingMiddleware(), s.MetricsMiddleware()}
+}
+
+// RecoverMiddleware recovers a panic of the run, records it and returns it as a *PanicError.
+// The recover handler of the process is called with the panic value, see WithRecover.
+func (s *Supervisor) RecoverMiddleware() Middleware {
+	return func(next ProcessFunc) ProcessFunc {
+		return func(ctx context.Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Value: r, Stack: debug.Stack()}
+
+					run, _ := runInfoFrom(ctx)
+					s.logger.Error("recover from panic", slog.String("process_name", run.name), slog.Any("panic", r))
+					s.metrics.recordProcessPanic(run.name)
+
+					if run.recoverHandler != nil {
+						run.recoverHandler(r)
+					}
+				}
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+// LoggingMiddleware logs the start of a run and its error.
+func (s *Supervisor) LoggingMiddleware() Middleware {
+	return func(next ProcessFunc) ProcessFunc {
+		return func(ctx context.Context) error {
+			name := ProcessName(ctx)
+			s.logger.Info("execute process", slog.String("process_name", name))
+
+			err := next(ctx)
+			if ctx.Err() != nil && s.isPaused(name) {
+				s.logger.Info("process paused", slog.String("process_name", name))
+			} else if err != nil {
+				s.logger.Error("process execution finished", slog.String("process_name", name),
+					slog.String("error", err.Error()))
+			}
+
+			return err
+		}
+	}
+}
+
+// MetricsMiddleware records the start of a run and how it stopped.
+func (s *Supervisor) MetricsMiddleware() Middleware {
+	return func(next ProcessFunc) ProcessFunc {
+		return func(ctx context.Context) error {
+			run, _ := runInfoFrom(ctx)
+			s.metrics.recordProcessStarted(ctx, run.name, run.restartPolicy)
+
+			err := next(ctx)
+			switch {
+			case ctx.Err() != nil && s.isPaused(run.name):
+				s.metrics.recordProcessStopped(run.name, "manual")
+			case err != nil:
+				s.metrics.recordProcessStopped(run.name, "error")
+			default:
+				s.metrics.recordProcessStopped(run.name, "success")
+			}
+
+			return err
+		}
+	}
+}
+
+// chain wraps handler with middlewares, the first middleware being the outermost one.
+func chain(handler ProcessFunc, middlewares ...Middleware) ProcessFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// getMiddlewares returns a copy of the supervisor chain
+func (s *Supervisor) getMiddlewares() []Middleware {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]Middleware(nil), s.middlewares...)
+}
@@ -0,0 +1,155 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMiddleware appends name to calls before and after the run.
+func recordingMiddleware(lock *sync.Mutex, calls *[]string, name string) Middleware {
+	return func(next ProcessFunc) ProcessFunc {
+		return func(ctx context.Context) error {
+			lock.Lock()
+			*calls = append(*calls, name+":before")
+			lock.Unlock()
+
+			err := next(ctx)
+
+			lock.Lock()
+			*calls = append(*calls, name+":after")
+			lock.Unlock()
+			return err
+		}
+	}
+}
+
+func TestSupervisor_MiddlewareOrder(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var lock sync.Mutex
+	var calls []string
+	s.Use(recordingMiddleware(&lock, &calls, "supervisor-1"), recordingMiddleware(&lock, &calls, "supervisor-2"))
+
+	done := make(chan struct{})
+	s.Register("worker", func(ctx context.Context) error {
+		lock.Lock()
+		calls = append(calls, "handler")
+		lock.Unlock()
+		close(done)
+		return nil
+	}, WithMiddleware(recordingMiddleware(&lock, &calls, "process")))
+	s.Run()
+	defer s.Shutdown()
+
+	<-done
+	waitForStatus(t, s, "worker", StatusStopped, time.Second)
+
+	expected := []string{
+		"supervisor-1:before", "supervisor-2:before", "process:before",
+		"handler",
+		"process:after", "supervisor-2:after", "supervisor-1:after",
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected call %d to be %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
+
+func TestSupervisor_MiddlewareGate(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	errDisabled := errors.New("feature disabled")
+	gate := func(next ProcessFunc) ProcessFunc {
+		return func(ctx context.Context) error {
+			return errDisabled
+		}
+	}
+
+	called := false
+	s.Register("worker", func(ctx context.Context) error {
+		called = true
+		return nil
+	}, WithMiddleware(gate))
+
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	s.Run()
+	defer s.Shutdown()
+
+	for event := range events {
+		if event.Type == EventStopped {
+			if !errors.Is(event.Err, errDisabled) {
+				t.Errorf("Expected gate error, got %v", event.Err)
+			}
+			break
+		}
+	}
+
+	if called {
+		t.Error("Expected gate to skip the handler")
+	}
+}
+
+func TestSupervisor_SetMiddlewareWithoutRecover(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	s.SetMiddleware(s.LoggingMiddleware())
+
+	recovered := make(chan any, 1)
+	s.Register("panicky", func(ctx context.Context) error {
+		panic("boom")
+	}, WithRecover(func(r any) { recovered <- r }))
+
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+
+	s.Run()
+	defer s.Shutdown()
+
+	for event := range events {
+		if event.Type == EventPanic {
+			var panicErr *PanicError
+			if !errors.As(event.Err, &panicErr) || panicErr.Value != "boom" {
+				t.Errorf("Expected *PanicError with value boom, got %v", event.Err)
+			}
+			break
+		}
+	}
+
+	// The recover handler belongs to RecoverMiddleware, which was removed
+	select {
+	case r := <-recovered:
+		t.Errorf("Expected recover handler not to be called, got %v", r)
+	default:
+	}
+}
+
+func TestSupervisor_RecoverMiddleware(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	recovered := make(chan any, 1)
+	s.Register("panicky", func(ctx context.Context) error {
+		panic("boom")
+	}, WithRecover(func(r any) { recovered <- r }))
+	s.Run()
+	defer s.Shutdown()
+
+	select {
+	case r := <-recovered:
+		if r != "boom" {
+			t.Errorf("Expected recovered value boom, got %v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected recover handler to be called")
+	}
+}
@@ -0,0 +1,139 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultNotifyTimeout bounds a single Notify call
+	DefaultNotifyTimeout = 10 * time.Second
+	// notificationQueueSize caps the notifications waiting to be sent
+	notificationQueueSize = 64
+)
+
+// Notifier sends alerts about events which need attention:
+// EventPanic, EventRestartLimitExceeded and EventShutdownTimeout.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface.
+type NotifierFunc func(ctx context.Context, event Event) error
+
+func (f NotifierFunc) Notify(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// WithNotifier adds notifiers which are called on panics, exceeded restart limits and shutdown timeouts.
+// Notifiers are called one at a time in the background and never block the supervisor.
+// Notifications are dropped if too many are pending. Pending notifications are sent at the end
+// of shutdown within what is left of the shutdown timeout.
+func WithNotifier(notifiers ...Notifier) SupervisorOption {
+	return func(s *Supervisor) {
+		s.notifiers = append(s.notifiers, notifiers...)
+	}
+}
+
+// notifications queues events for the notifiers.
+type notifications struct {
+	lock   sync.Mutex
+	queue  chan Event
+	closed bool
+	done   chan struct{} // Closed when the queue is drained
+}
+
+// isAlert reports whether notifiers are called for an event of type t.
+func isAlert(t EventType) bool {
+	switch t {
+	case EventPanic, EventRestartLimitExceeded, EventShutdownTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// startNotifications starts sending events to the notifiers, if any.
+func (s *Supervisor) startNotifications() {
+	if len(s.notifiers) == 0 {
+		return
+	}
+
+	s.notifications = &notifications{
+		queue: make(chan Event, notificationQueueSize),
+		done:  make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.notifications.done)
+
+		for event := range s.notifications.queue {
+			for _, notifier := range s.notifiers {
+				s.sendNotification(notifier, event)
+			}
+		}
+	}()
+}
+
+func (s *Supervisor) sendNotification(notifier Notifier, event Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultNotifyTimeout)
+	defer cancel()
+
+	if err := notifier.Notify(ctx, event); err != nil {
+		s.logger.Error("failed to send notification",
+			slog.String("process_name", event.Process),
+			slog.String("event", event.Type.String()),
+			slog.String("error", err.Error()))
+	}
+}
+
+// notify queues an alert event for the notifiers.
+func (s *Supervisor) notify(event Event) {
+	if s.notifications == nil || !isAlert(event.Type) {
+		return
+	}
+
+	s.notifications.lock.Lock()
+	defer s.notifications.lock.Unlock()
+
+	if s.notifications.closed {
+		return
+	}
+
+	select {
+	case s.notifications.queue <- event:
+	default:
+		s.logger.Warn("too many pending notifications, dropping notification",
+			slog.String("process_name", event.Process),
+			slog.String("event", event.Type.String()))
+	}
+}
+
+// flushNotifications waits up to timeout for pending notifications to be sent and stops the notifiers.
+func (s *Supervisor) flushNotifications(timeout time.Duration) {
+	if s.notifications == nil {
+		return
+	}
+
+	s.notifications.lock.Lock()
+	if !s.notifications.closed {
+		s.notifications.closed = true
+		close(s.notifications.queue)
+	}
+	s.notifications.lock.Unlock()
+
+	if timeout <= 0 {
+		if !isClosed(s.notifications.done) {
+			s.logger.Warn("no shutdown time left to send pending notifications")
+		}
+		return
+	}
+
+	select {
+	case <-s.notifications.done:
+	case <-s.clock.After(timeout):
+		s.logger.Warn("pending notifications were not sent within the shutdown timeout")
+	}
+}
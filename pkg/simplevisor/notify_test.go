@@ -0,0 +1,129 @@
+package simplevisor
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingNotifier records the events it is notified about.
+type recordingNotifier struct {
+	lock   sync.Mutex
+	events []Event
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, event Event) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.events = append(n.events, event)
+	return nil
+}
+
+func (n *recordingNotifier) types() []EventType {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	var types []EventType
+	for _, event := range n.events {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func TestWithNotifier_PanicAndRestartLimit(t *testing.T) {
+	notifier := &recordingNotifier{}
+	s := New(time.Second, slog.New(slog.DiscardHandler), WithNotifier(notifier))
+
+	s.Register("crashing", func(ctx context.Context) error {
+		panic("boom")
+	}, WithRestart(RestartOnFailure, 1, 10*time.Millisecond))
+	s.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for !slices.Contains(notifier.types(), EventRestartLimitExceeded) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.Shutdown()
+
+	got := notifier.types()
+	if len(got) < 2 || got[len(got)-1] != EventRestartLimitExceeded {
+		t.Fatalf("expected panics followed by restart limit exceeded, got %v", got)
+	}
+	for _, eventType := range got[:len(got)-1] {
+		if eventType != EventPanic {
+			t.Errorf("expected only panics before the restart limit, got %v", got)
+		}
+	}
+}
+
+func TestWithNotifier_PendingNotificationsAreSentOnShutdown(t *testing.T) {
+	notifier := &recordingNotifier{}
+	s := New(time.Second, slog.New(slog.DiscardHandler), WithNotifier(NotifierFunc(
+		func(ctx context.Context, event Event) error {
+			// A slow notifier is waited for within the shutdown timeout
+			time.Sleep(50 * time.Millisecond)
+			return notifier.Notify(ctx, event)
+		})))
+
+	panicked := make(chan struct{})
+	s.Register("crashing", func(ctx context.Context) error {
+		close(panicked)
+		panic("boom")
+	})
+	s.Run()
+
+	<-panicked
+	s.Shutdown()
+
+	if got := notifier.types(); len(got) != 1 || got[0] != EventPanic {
+		t.Errorf("expected panic notification, got %v", got)
+	}
+}
+
+func TestWithNotifier_FlushIsBoundedByShutdownTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	notified := make(chan struct{}, 1)
+	s := New(100*time.Millisecond, slog.New(slog.DiscardHandler), WithNotifier(NotifierFunc(
+		func(ctx context.Context, event Event) error {
+			notified <- struct{}{}
+			<-release
+			return nil
+		})))
+
+	s.Register("crashing", func(ctx context.Context) error {
+		panic("boom")
+	})
+	s.Run()
+
+	<-notified
+	start := time.Now()
+	s.Shutdown()
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected shutdown within its timeout, took %v", elapsed)
+	}
+}
+
+func TestWithNotifier_IgnoresOtherEvents(t *testing.T) {
+	notifier := &recordingNotifier{}
+	s := New(time.Second, slog.New(slog.DiscardHandler), WithNotifier(notifier))
+
+	s.Register("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+	waitForStatus(t, s, "worker", StatusRunning, time.Second)
+	s.Shutdown()
+
+	if got := notifier.types(); len(got) != 0 {
+		t.Errorf("expected no notifications, got %v", got)
+	}
+}
@@ -0,0 +1,104 @@
+package simplevisor
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Pause stops a process while keeping it registered, e.g. to stop a consumer from pulling messages
+// during an incident. The current run is cancelled without counting as a failure and the process
+// stays in StatusPaused until Resume. Pause waits up to the shutdown timeout for the run to exit.
+func (s *Supervisor) Pause(name string) error {
+	s.lock.Lock()
+	process, ok := s.processes[name]
+	if !ok || process.removed {
+		s.lock.Unlock()
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	if process.paused {
+		s.lock.Unlock()
+		return nil
+	}
+
+	process.paused = true
+	s.processes[name] = process
+
+	stopping := make(map[string]chan struct{})
+	if process.done != nil && !isClosed(process.done) {
+		process.cancel()
+		stopping[name] = process.done
+	}
+	s.lock.Unlock()
+
+	s.logger.Info("pausing process", slog.String("process_name", name))
+
+	if pending := s.waitForExit(stopping); len(pending) > 0 {
+		return fmt.Errorf("process %s did not exit within %v", name, s.shutdownTimeout)
+	}
+
+	s.setProcessStatus(name, StatusPaused)
+	s.publish(Event{Type: EventPaused, Process: name})
+
+	return nil
+}
+
+// Resume starts a paused process with a fresh restart budget.
+// If the supervisor isn't running yet, the process is started by Run.
+// Returns ErrDraining once shutdown started.
+func (s *Supervisor) Resume(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if isClosed(s.draining) {
+		return ErrDraining
+	}
+
+	process, ok := s.processes[name]
+	if !ok || process.removed {
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	if !process.paused {
+		return fmt.Errorf("process %s is not paused", name)
+	}
+
+	if process.done != nil && !isClosed(process.done) {
+		return fmt.Errorf("process %s is still pausing", name)
+	}
+
+	process.paused = false
+	s.processes[name] = process
+	s.resetRestartBudgetLocked(name)
+
+	s.logger.Info("resuming process", slog.String("process_name", name))
+	s.publish(Event{Type: EventResumed, Process: name})
+
+	if s.canSpawnLocked() && !process.stopped {
+		s.spawnLocked(name)
+	} else {
+		s.setProcessStatusLocked(name, StatusStopped)
+	}
+
+	return nil
+}
+
+// setExitStatus sets the status of a process whose goroutine exits.
+func (s *Supervisor) setExitStatus(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	status := StatusStopped
+	if process, exists := s.processes[name]; exists && process.paused {
+		status = StatusPaused
+	}
+	s.setProcessStatusLocked(name, status)
+}
+
+// isPaused reports whether a process is paused
+func (s *Supervisor) isPaused(name string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.processes[name].paused
+}
@@ -0,0 +1,138 @@
+package simplevisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_PauseResume(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var runs atomic.Int32
+	s.Register("consumer", func(ctx context.Context) error {
+		runs.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithRestart(RestartOnFailure, 2, 10*time.Millisecond))
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "consumer", StatusRunning, time.Second)
+
+	if err := s.Pause("consumer"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+
+	info, err := s.GetProcessInfo("consumer")
+	if err != nil {
+		t.Fatalf("GetProcessInfo failed: %v", err)
+	}
+	if info.Status != StatusPaused {
+		t.Errorf("Expected status paused, got %v", info.Status)
+	}
+	if info.RestartCount != 0 {
+		t.Errorf("Expected pause not to consume restart budget, got restart count %d", info.RestartCount)
+	}
+
+	// A paused process is not restarted
+	time.Sleep(50 * time.Millisecond)
+	if status, _ := s.GetProcessStatus("consumer"); status != StatusPaused {
+		t.Errorf("Expected process to stay paused, got %v", status)
+	}
+
+	// Pausing twice is a no-op
+	if err := s.Pause("consumer"); err != nil {
+		t.Errorf("Expected second Pause to succeed, got %v", err)
+	}
+
+	if err := s.Resume("consumer"); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	waitForStatus(t, s, "consumer", StatusRunning, time.Second)
+
+	if got := runs.Load(); got != 2 {
+		t.Errorf("Expected 2 runs, got %d", got)
+	}
+
+	if err := s.Resume("consumer"); err == nil {
+		t.Error("Expected error when resuming a process which is not paused")
+	}
+}
+
+func TestSupervisor_ResumeResetsRestartCount(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var runs atomic.Int32
+	s.Register("flaky", func(ctx context.Context) error {
+		if runs.Add(1) <= 2 {
+			return context.DeadlineExceeded
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithRestart(RestartOnFailure, 5, 10*time.Millisecond))
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "flaky", StatusRunning, time.Second)
+	for runs.Load() < 3 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	waitForStatus(t, s, "flaky", StatusRunning, time.Second)
+
+	if count := s.getRestartCount("flaky"); count != 2 {
+		t.Fatalf("Expected restart count 2 before pause, got %d", count)
+	}
+
+	if err := s.Pause("flaky"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if err := s.Resume("flaky"); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	waitForStatus(t, s, "flaky", StatusRunning, time.Second)
+
+	if count := s.getRestartCount("flaky"); count != 0 {
+		t.Errorf("Expected restart count reset on resume, got %d", count)
+	}
+}
+
+func TestSupervisor_PauseBeforeRun(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var runs atomic.Int32
+	s.Register("consumer", func(ctx context.Context) error {
+		runs.Add(1)
+		<-ctx.Done()
+		return nil
+	})
+
+	if err := s.Pause("consumer"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+
+	s.Run()
+	defer s.Shutdown()
+
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Errorf("Expected paused process not to be started by Run, got %d runs", runs.Load())
+	}
+
+	if err := s.Resume("consumer"); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	waitForStatus(t, s, "consumer", StatusRunning, time.Second)
+}
+
+func TestSupervisor_PauseUnknownProcess(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	if err := s.Pause("missing"); err == nil {
+		t.Error("Expected error when pausing an unknown process")
+	}
+	if err := s.Resume("missing"); err == nil {
+		t.Error("Expected error when resuming an unknown process")
+	}
+}
@@ -0,0 +1,227 @@
+package simplevisor
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// pool holds the settings to create replicas of a pool.
+type pool struct {
+	name    string
+	handler ProcessFunc
+	options []Option
+	size    int
+}
+
+// PoolInfo is a snapshot of a pool and its replicas.
+type PoolInfo struct {
+	Name     string
+	Size     int
+	Running  int
+	Replicas []ProcessInfo // Sorted by replica index
+}
+
+// RegisterPool registers size supervised replicas of the same handler.
+// Replicas are named "<name>-<index>" and receive their index in the context, see ReplicaIndex.
+// Options apply to every replica. Replicas are started right away if the supervisor is running
+// and not shutting down.
+// Panics if the name or the name of a replica isn't unique or size is negative.
+func (s *Supervisor) RegisterPool(name string, size int, handler ProcessFunc, options ...Option) {
+	if size < 0 {
+		panic(fmt.Sprintf("invalid size %d for pool %q", size, name))
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Check every name first so a collision doesn't leave a partial pool behind
+	s.panicIfNameAlreadyInUseLocked(name)
+	for i := range size {
+		s.panicIfNameAlreadyInUseLocked(replicaName(name, i))
+	}
+
+	p := pool{name: name, handler: handler, options: options}
+	for i := range size {
+		if err := s.addReplicaLocked(p, i); err != nil {
+			panic(err.Error())
+		}
+		if s.canSpawnLocked() {
+			s.spawnLocked(replicaName(name, i))
+		}
+	}
+
+	p.size = size
+	s.pools[name] = p
+	s.updatePoolMetricsLocked(name)
+}
+
+// Scale changes the number of replicas of a pool.
+// New replicas are started right away if the supervisor is running.
+// Removed replicas are the ones with the highest indices; their contexts are cancelled
+// and Scale waits up to the shutdown timeout for them to exit.
+// Returns ErrDraining once shutdown started.
+func (s *Supervisor) Scale(name string, size int) error {
+	if size < 0 {
+		return fmt.Errorf("invalid size %d for pool %s", size, name)
+	}
+
+	s.lock.Lock()
+	p, ok := s.pools[name]
+	if !ok {
+		s.lock.Unlock()
+		return fmt.Errorf("pool %s not found", name)
+	}
+	if isClosed(s.draining) {
+		s.lock.Unlock()
+		return ErrDraining
+	}
+
+	s.logger.Info("scaling pool",
+		slog.String("pool_name", name),
+		slog.Int("from", p.size),
+		slog.Int("to", size))
+
+	for i := p.size; i < size; i++ {
+		if err := s.addReplicaLocked(p, i); err != nil {
+			p.size = i
+			s.pools[name] = p
+			s.updatePoolMetricsLocked(name)
+			s.lock.Unlock()
+			return err
+		}
+		if s.canSpawnLocked() {
+			s.spawnLocked(replicaName(name, i))
+		}
+	}
+
+	removing := make(map[string]chan struct{})
+	for i := p.size - 1; i >= size; i-- {
+		replica := replicaName(name, i)
+		process := s.processes[replica]
+		if process.done == nil {
+			s.deleteProcessLocked(replica)
+			continue
+		}
+
+		process.removed = true
+		s.processes[replica] = process
+		process.cancel()
+		removing[replica] = process.done
+	}
+
+	p.size = size
+	s.pools[name] = p
+	s.updatePoolMetricsLocked(name)
+	s.lock.Unlock()
+
+	return s.waitForRemoval(name, removing)
+}
+
+// waitForRemoval waits for removed replicas to exit within the shutdown timeout.
+func (s *Supervisor) waitForRemoval(pool string, removing map[string]chan struct{}) error {
+	if pending := s.waitForExit(removing); len(pending) > 0 {
+		return fmt.Errorf("pool %s: replicas %s did not exit within %v",
+			pool, strings.Join(pending, ", "), s.shutdownTimeout)
+	}
+
+	for replica, done := range removing {
+		s.leaveIfRemoved(replica, done)
+		s.logger.Info("pool replica removed", slog.String("process_name", replica))
+	}
+
+	return nil
+}
+
+// PoolStatus returns a snapshot of a pool and its replicas
+func (s *Supervisor) PoolStatus(name string) (PoolInfo, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p, ok := s.pools[name]
+	if !ok {
+		return PoolInfo{}, fmt.Errorf("pool %s not found", name)
+	}
+
+	info := PoolInfo{Name: name, Size: p.size}
+	for _, process := range s.processes {
+		if process.pool != name || process.removed {
+			continue
+		}
+
+		info.Replicas = append(info.Replicas, process.info())
+		if process.status == StatusRunning {
+			info.Running++
+		}
+	}
+	sort.Slice(info.Replicas, func(i, j int) bool {
+		return info.Replicas[i].Replica < info.Replicas[j].Replica
+	})
+
+	return info, nil
+}
+
+// addReplicaLocked registers replica index of pool p. The caller must hold s.lock.
+func (s *Supervisor) addReplicaLocked(p pool, index int) error {
+	name := replicaName(p.name, index)
+	if _, ok := s.processes[name]; ok {
+		return fmt.Errorf("process name %q already in use", name)
+	}
+
+	process := newProcess(name, p.handler, p.options...)
+	s.applyConfig(&process, p.name)
+	process.pool = p.name
+	process.replica = index
+	s.processes[name] = process
+	s.metrics.setProcessLabels(name, process.labels)
+	s.metrics.updateTotalProcesses(1, StatusStopped)
+
+	return nil
+}
+
+// leaveIfRemoved deletes a scaled down process once its goroutine exits.
+func (s *Supervisor) leaveIfRemoved(name string, done chan struct{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if process, ok := s.processes[name]; ok && process.removed && process.done == done {
+		s.deleteProcessLocked(name)
+	}
+}
+
+// deleteProcessLocked removes a process from the supervisor. The caller must hold s.lock.
+func (s *Supervisor) deleteProcessLocked(name string) {
+	process, ok := s.processes[name]
+	if !ok {
+		return
+	}
+
+	delete(s.processes, name)
+	s.metrics.setProcessLabels(name, nil)
+	s.metrics.updateTotalProcesses(-1, process.status)
+	if process.pool != "" {
+		s.updatePoolMetricsLocked(process.pool)
+	}
+}
+
+// updatePoolMetricsLocked records the size and running replicas of a pool. The caller must hold s.lock.
+func (s *Supervisor) updatePoolMetricsLocked(name string) {
+	p, ok := s.pools[name]
+	if !ok {
+		return
+	}
+
+	running := 0
+	for _, process := range s.processes {
+		if process.pool == name && process.status == StatusRunning {
+			running++
+		}
+	}
+
+	s.metrics.updatePoolReplicas(name, p.size, running)
+}
+
+func replicaName(pool string, index int) string {
+	return fmt.Sprintf("%s-%d", pool, index)
+}
@@ -0,0 +1,208 @@
+package simplevisor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func waitForPoolRunning(t *testing.T, s *Supervisor, name string, expected int, timeout time.Duration) {
+	t.Helper()
+	start := time.Now()
+	for time.Since(start) < timeout {
+		if info, err := s.PoolStatus(name); err == nil && info.Running == expected {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Pool %s did not reach %d running replicas within %v", name, expected, timeout)
+}
+
+func TestSupervisor_RegisterPool(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var lock sync.Mutex
+	indices := make(map[int]bool)
+	handler := func(ctx context.Context) error {
+		index, ok := ReplicaIndex(ctx)
+		if !ok {
+			t.Error("Replica index should be in the context")
+		}
+
+		lock.Lock()
+		indices[index] = true
+		lock.Unlock()
+
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	s.RegisterPool("consumer", 3, handler)
+
+	if s.ProcessCount() != 3 {
+		t.Errorf("Expected 3 processes, got %d", s.ProcessCount())
+	}
+
+	s.Run()
+	waitForPoolRunning(t, s, "consumer", 3, time.Second)
+
+	info, err := s.PoolStatus("consumer")
+	if err != nil {
+		t.Fatalf("Failed to get pool status: %v", err)
+	}
+
+	if info.Size != 3 || len(info.Replicas) != 3 {
+		t.Errorf("Expected 3 replicas, got size %d with %d replicas", info.Size, len(info.Replicas))
+	}
+
+	for i, replica := range info.Replicas {
+		if replica.Replica != i || replica.Name != replicaName("consumer", i) || replica.Pool != "consumer" {
+			t.Errorf("Unexpected replica info %+v at index %d", replica, i)
+		}
+	}
+
+	s.Shutdown()
+
+	lock.Lock()
+	defer lock.Unlock()
+	for i := range 3 {
+		if !indices[i] {
+			t.Errorf("Replica %d should have been executed", i)
+		}
+	}
+}
+
+func TestSupervisor_RegisterPoolDuplicateName(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	handler := func(ctx context.Context) error { return nil }
+
+	s.RegisterPool("consumer", 1, handler)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic on duplicate pool registration")
+		}
+	}()
+
+	s.RegisterPool("consumer", 2, handler)
+}
+
+func TestSupervisor_RegisterPoolNameCollisions(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	handler := func(ctx context.Context) error { return nil }
+
+	s.Register("worker", handler)
+	s.Register("consumer-1", handler)
+	s.RegisterPool("producer", 1, handler)
+
+	for name, register := range map[string]func(){
+		"pool named like a process":    func() { s.RegisterPool("worker", 1, handler) },
+		"replica named like a process": func() { s.RegisterPool("consumer", 2, handler) },
+		"process named like a pool":    func() { s.Register("producer", handler) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic for %s", name)
+				}
+			}()
+			register()
+		}()
+	}
+
+	// A rejected pool registers none of its replicas
+	if s.ProcessCount() != 3 {
+		t.Errorf("Expected 3 processes, got %d", s.ProcessCount())
+	}
+}
+
+func TestSupervisor_RegisterPoolAfterRun(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	s.Run()
+	defer s.Shutdown()
+
+	s.RegisterPool("consumer", 2, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	waitForPoolRunning(t, s, "consumer", 2, time.Second)
+}
+
+func TestSupervisor_Scale(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	handler := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	s.RegisterPool("consumer", 2, handler, WithRestart(RestartAlways, 3, 10*time.Millisecond))
+	s.Run()
+	waitForPoolRunning(t, s, "consumer", 2, time.Second)
+
+	if err := s.Scale("consumer", 4); err != nil {
+		t.Fatalf("Failed to scale up: %v", err)
+	}
+	waitForPoolRunning(t, s, "consumer", 4, time.Second)
+
+	if err := s.Scale("consumer", 1); err != nil {
+		t.Fatalf("Failed to scale down: %v", err)
+	}
+
+	info, _ := s.PoolStatus("consumer")
+	if info.Size != 1 || len(info.Replicas) != 1 || info.Replicas[0].Replica != 0 {
+		t.Errorf("Expected only replica 0 to remain, got %+v", info)
+	}
+
+	if s.ProcessCount() != 1 {
+		t.Errorf("Expected 1 process, got %d", s.ProcessCount())
+	}
+
+	if !s.IsRunning(replicaName("consumer", 0)) {
+		t.Error("Replica 0 should keep running")
+	}
+
+	if err := s.Scale("consumer", 2); err != nil {
+		t.Fatalf("Failed to scale up again: %v", err)
+	}
+	waitForPoolRunning(t, s, "consumer", 2, time.Second)
+
+	s.Shutdown()
+}
+
+func TestSupervisor_ScaleBeforeRun(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	handler := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	s.RegisterPool("consumer", 3, handler)
+
+	if err := s.Scale("consumer", 1); err != nil {
+		t.Fatalf("Failed to scale down: %v", err)
+	}
+
+	if s.ProcessCount() != 1 {
+		t.Errorf("Expected 1 process, got %d", s.ProcessCount())
+	}
+
+	s.Run()
+	waitForPoolRunning(t, s, "consumer", 1, time.Second)
+	s.Shutdown()
+}
+
+func TestSupervisor_ScaleErrors(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	if err := s.Scale("missing", 1); err == nil {
+		t.Error("Expected error for unknown pool")
+	}
+
+	s.RegisterPool("consumer", 1, func(ctx context.Context) error { return nil })
+	if err := s.Scale("consumer", -1); err == nil {
+		t.Error("Expected error for negative size")
+	}
+}
@@ -0,0 +1,189 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRollingRestartTimeout is how long a rolling restart waits for a process to come back
+	DefaultRollingRestartTimeout = 30 * time.Second
+	// rollingRestartPollInterval is how often a rolling restart checks whether a process is back
+	rollingRestartPollInterval = 10 * time.Millisecond
+)
+
+// RollingRestartOptions configures RollingRestart.
+type RollingRestartOptions struct {
+	Selector    Selector      // Processes to restart, every process if empty
+	Concurrency int           // Processes restarted at a time, 1 if <= 0
+	Timeout     time.Duration // Wait for a process to come back, DefaultRollingRestartTimeout if <= 0
+	// Progress is called after every restarted process, one call at a time.
+	Progress func(RollingRestartProgress)
+}
+
+// RollingRestartProgress reports a process restarted by RollingRestart.
+type RollingRestartProgress struct {
+	Process   string
+	Restarted int // Processes restarted so far, including this one
+	Total     int
+	Err       error // Set if the process didn't come back, which aborts the rolling restart
+}
+
+// RollingRestart restarts the running processes matching opts.Selector a few at a time,
+// e.g. after a config rotation, without a full outage.
+// Each process is back once its new run started or, for processes with a startup timeout,
+// once it signalled readiness, see WithStartupTimeout. A leader-elected process is also back
+// once it waits for its lease in standby.
+// The rolling restart stops at the first process which doesn't come back within the timeout
+// and returns its error; processes already being restarted are waited for.
+// Paused, stopped and standby processes are skipped.
+func (s *Supervisor) RollingRestart(ctx context.Context, opts RollingRestartOptions) error {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultRollingRestartTimeout
+	}
+
+	s.lock.Lock()
+	if !s.running {
+		s.lock.Unlock()
+		return errors.New("supervisor is not running")
+	}
+
+	var names []string
+	for _, name := range s.selectLocked(opts.Selector) {
+		process := s.processes[name]
+		if process.done != nil && !isClosed(process.done) && !process.paused && !process.stopped &&
+			process.status != StatusStandby {
+			names = append(names, name)
+		}
+	}
+	s.lock.Unlock()
+
+	s.logger.Info("starting rolling restart",
+		slog.Any("selector", opts.Selector),
+		slog.Int("processes", len(names)),
+		slog.Int("concurrency", opts.Concurrency))
+
+	// Aborting stops handing out processes; processes being restarted are still waited for
+	abortCtx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+
+	var (
+		wg        sync.WaitGroup
+		lock      sync.Mutex
+		restarted int
+	)
+	queue := make(chan string)
+
+	for range opts.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for name := range queue {
+				if abortCtx.Err() != nil {
+					continue
+				}
+
+				err := s.restartAndAwait(ctx, name, opts.Timeout)
+
+				lock.Lock()
+				restarted++
+				s.logger.Info("rolling restart progress",
+					slog.String("process_name", name),
+					slog.Int("restarted", restarted),
+					slog.Int("total", len(names)),
+					slog.Any("error", err))
+				if opts.Progress != nil {
+					opts.Progress(RollingRestartProgress{Process: name, Restarted: restarted, Total: len(names), Err: err})
+				}
+				lock.Unlock()
+
+				if err != nil {
+					abort(err)
+				}
+			}
+		}()
+	}
+
+schedule:
+	for _, name := range names {
+		select {
+		case queue <- name:
+		case <-abortCtx.Done():
+			break schedule
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := context.Cause(abortCtx); err != nil {
+		s.logger.Error("rolling restart aborted", slog.String("error", err.Error()))
+		return fmt.Errorf("rolling restart aborted: %w", err)
+	}
+
+	s.logger.Info("rolling restart finished", slog.Int("processes", len(names)))
+	return nil
+}
+
+// restartAndAwait restarts a process and waits until its new run started or, with a startup timeout, is ready.
+// It polls the process instead of subscribing to events, which are dropped for a subscriber that is behind.
+func (s *Supervisor) restartAndAwait(ctx context.Context, name string, timeout time.Duration) error {
+	s.lock.Lock()
+	awaitReady := s.processes[name].startupTimeout > 0
+	attempts := s.processes[name].attempts
+	s.lock.Unlock()
+
+	if err := s.restart([]string{name}); err != nil {
+		return err
+	}
+
+	deadline := s.clock.After(timeout)
+	ticker := time.NewTicker(rollingRestartPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if back, err := s.isBack(name, attempts, awaitReady); back || err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return fmt.Errorf("process %s did not come back within %v", name, timeout)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// isBack reports whether a restarted process, which had the given number of attempts before, is back.
+// It returns an error if the new run stopped before it was ready.
+func (s *Supervisor) isBack(name string, attempts int, awaitReady bool) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	process, exists := s.processes[name]
+	switch {
+	case !exists:
+		return false, fmt.Errorf("process %s was removed", name)
+	case process.status == StatusStandby:
+		// Another replica took the lease, the process is back waiting for it
+		return true, nil
+	case process.attempts <= attempts:
+		return false, nil // The new run hasn't started yet
+	case process.status != StatusRunning:
+		if n := len(process.exits); n > 0 && process.exits[n-1].Err != nil {
+			return false, fmt.Errorf("process %s stopped before it was ready: %w", name, process.exits[n-1].Err)
+		}
+		return false, fmt.Errorf("process %s stopped before it was ready", name)
+	default:
+		return !awaitReady || process.ready, nil
+	}
+}
@@ -0,0 +1,224 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_RollingRestart(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	handler := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	s.RegisterPool("consumer", 3, handler, WithLabels(map[string]string{"broker": "rabbit"}))
+	s.Register("http", handler)
+	s.Run()
+	defer s.Shutdown()
+
+	waitForPoolRunning(t, s, "consumer", 3, time.Second)
+	waitForStatus(t, s, "http", StatusRunning, time.Second)
+
+	// Track how many selected processes are down at the same time
+	var down, maxDown atomic.Int32
+	events, unsubscribe := s.Subscribe(0)
+	defer unsubscribe()
+	go func() {
+		for event := range events {
+			switch event.Type {
+			case EventStopped:
+				if n := down.Add(1); n > maxDown.Load() {
+					maxDown.Store(n)
+				}
+			case EventStarted:
+				down.Add(-1)
+			}
+		}
+	}()
+
+	var progress []RollingRestartProgress
+	err := s.RollingRestart(context.Background(), RollingRestartOptions{
+		Selector: Selector{"broker": "rabbit"},
+		Progress: func(p RollingRestartProgress) {
+			progress = append(progress, p)
+		},
+	})
+	if err != nil {
+		t.Fatalf("RollingRestart failed: %v", err)
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("Expected 3 progress reports, got %d", len(progress))
+	}
+	for i, p := range progress {
+		if p.Restarted != i+1 || p.Total != 3 || p.Err != nil {
+			t.Errorf("Unexpected progress %d: %+v", i, p)
+		}
+	}
+
+	if got := maxDown.Load(); got > 1 {
+		t.Errorf("Expected at most one process down at a time, got %d", got)
+	}
+
+	for _, info := range s.Processes(nil) {
+		expected := 2
+		if info.Name == "http" {
+			expected = 1
+		}
+		if info.Attempts != expected {
+			t.Errorf("Expected %d runs of %s, got %d", expected, info.Name, info.Attempts)
+		}
+	}
+}
+
+func TestSupervisor_RollingRestartWaitsForReady(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var ready atomic.Bool
+	s.Register("server", func(ctx context.Context) error {
+		if Attempt(ctx) > 1 {
+			time.Sleep(50 * time.Millisecond)
+			ready.Store(true)
+		}
+		Ready(ctx)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithStartupTimeout(time.Second))
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "server", StatusRunning, time.Second)
+
+	if err := s.RollingRestart(context.Background(), RollingRestartOptions{}); err != nil {
+		t.Fatalf("RollingRestart failed: %v", err)
+	}
+	if !ready.Load() {
+		t.Error("Expected rolling restart to wait until the process is ready")
+	}
+}
+
+func TestSupervisor_RollingRestartAbortsOnFailure(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	errBroken := errors.New("bad credentials")
+	handler := func(ctx context.Context) error {
+		if Attempt(ctx) > 1 && ProcessName(ctx) == "a" {
+			return errBroken
+		}
+		Ready(ctx)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	s.Register("a", handler, WithStartupTimeout(time.Second))
+	s.Register("b", handler, WithStartupTimeout(time.Second))
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "a", StatusRunning, time.Second)
+	waitForStatus(t, s, "b", StatusRunning, time.Second)
+
+	var progress []RollingRestartProgress
+	err := s.RollingRestart(context.Background(), RollingRestartOptions{
+		Progress: func(p RollingRestartProgress) {
+			progress = append(progress, p)
+		},
+	})
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Expected rolling restart to fail with the process error, got %v", err)
+	}
+
+	if len(progress) != 1 || progress[0].Process != "a" {
+		t.Errorf("Expected to stop after the first process, got %+v", progress)
+	}
+	if info, _ := s.GetProcessInfo("b"); info.Attempts != 1 {
+		t.Errorf("Expected b not to be restarted, got %d runs", info.Attempts)
+	}
+}
+
+func TestSupervisor_RollingRestartNotRunning(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	if err := s.RollingRestart(context.Background(), RollingRestartOptions{}); err == nil {
+		t.Error("Expected error before Run")
+	}
+}
+
+func TestSupervisor_RollingRestartWithEventFlood(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	s.Register("server", func(ctx context.Context) error {
+		Ready(ctx)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithStartupTimeout(time.Second))
+	s.Run()
+	defer s.Shutdown()
+
+	waitForStatus(t, s, "server", StatusRunning, time.Second)
+
+	// Events of other processes fill up any event subscription
+	stop := make(chan struct{})
+	flooded := make(chan struct{})
+	go func() {
+		defer close(flooded)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				s.publish(Event{Type: EventRestarting, Process: "noisy"})
+			}
+		}
+	}()
+
+	err := s.RollingRestart(context.Background(), RollingRestartOptions{Timeout: 500 * time.Millisecond})
+	close(stop)
+	<-flooded
+
+	if err != nil {
+		t.Fatalf("RollingRestart failed: %v", err)
+	}
+}
+
+func TestSupervisor_RollingRestartSkipsStandby(t *testing.T) {
+	locker := NewMemoryLocker()
+	handler := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	leader := createTestSupervisor(time.Second)
+	leader.Register("scheduler", handler, WithLeaderElection(locker))
+	leader.Run()
+	defer leader.Shutdown()
+	waitForStatus(t, leader, "scheduler", StatusRunning, time.Second)
+
+	standby := createTestSupervisor(time.Second)
+	standby.Register("scheduler", handler, WithLeaderElection(locker))
+	standby.Run()
+	defer standby.Shutdown()
+	waitForStatus(t, standby, "scheduler", StatusStandby, time.Second)
+
+	var restarted []string
+	err := standby.RollingRestart(context.Background(), RollingRestartOptions{
+		Timeout: 200 * time.Millisecond,
+		Progress: func(p RollingRestartProgress) {
+			restarted = append(restarted, p.Process)
+		},
+	})
+	if err != nil {
+		t.Fatalf("RollingRestart failed: %v", err)
+	}
+	if len(restarted) != 0 {
+		t.Errorf("Expected standby process to be skipped, restarted %v", restarted)
+	}
+
+	// The leader is back once the other replica takes its lease while it restarts
+	if err := leader.RollingRestart(context.Background(), RollingRestartOptions{Timeout: time.Second}); err != nil {
+		t.Errorf("RollingRestart of the leader failed: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package simplevisortest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hasnpr/gohabit/pkg/simplevisor"
+)
+
+// FakeClock is a simplevisor.Clock which only moves when advanced.
+type FakeClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	changed chan struct{} // Closed and replaced whenever a timer is added
+}
+
+var _ simplevisor.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now:     now,
+		changed: make(chan struct{}),
+	}
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+// Since returns the fake time elapsed since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// After returns a channel which receives the fake time once it is advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.schedule(d, func(now time.Time) {
+		ch <- now
+	})
+
+	return ch
+}
+
+// AfterFunc calls f in its own goroutine once the fake time is advanced by d.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) simplevisor.Timer {
+	return c.schedule(d, func(time.Time) {
+		go f()
+	})
+}
+
+// Advance moves the fake time forward and fires all timers which are due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+
+	var due, pending []*fakeTimer
+	for _, timer := range c.timers {
+		if timer.at.After(now) {
+			pending = append(pending, timer)
+		} else {
+			due = append(due, timer)
+		}
+	}
+	c.timers = pending
+	c.lock.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].at.Before(due[j].at)
+	})
+	for _, timer := range due {
+		timer.fire(now)
+	}
+}
+
+// Timers returns the number of pending timers.
+func (c *FakeClock) Timers() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.timers)
+}
+
+// BlockUntil blocks until at least n timers are pending.
+// Use it to make sure the supervisor waits on the clock before calling Advance.
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.lock.Lock()
+		if len(c.timers) >= n {
+			c.lock.Unlock()
+			return
+		}
+		changed := c.changed
+		c.lock.Unlock()
+
+		<-changed
+	}
+}
+
+func (c *FakeClock) schedule(d time.Duration, fire func(now time.Time)) *fakeTimer {
+	c.lock.Lock()
+
+	timer := &fakeTimer{clock: c, at: c.now.Add(d), onFire: fire}
+	if d <= 0 {
+		now := c.now
+		c.lock.Unlock()
+		timer.fire(now)
+		return timer
+	}
+
+	c.timers = append(c.timers, timer)
+	close(c.changed)
+	c.changed = make(chan struct{})
+	c.lock.Unlock()
+
+	return timer
+}
+
+func (c *FakeClock) remove(timer *fakeTimer) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i, t := range c.timers {
+		if t == timer {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	at     time.Time
+	onFire func(now time.Time)
+	once   sync.Once
+}
+
+func (t *fakeTimer) fire(now time.Time) {
+	t.once.Do(func() {
+		t.onFire(now)
+	})
+}
+
+// Stop prevents the timer from firing.
+func (t *fakeTimer) Stop() bool {
+	return t.clock.remove(t)
+}
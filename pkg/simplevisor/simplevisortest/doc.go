@@ -0,0 +1,25 @@
+// Package simplevisortest provides utilities for testing code supervised by simplevisor.
+//
+// A FakeClock makes restart delays, healthy durations and shutdown timeouts
+// deterministic:
+//
+//	clock := simplevisortest.NewFakeClock(time.Now())
+//	s := simplevisor.New(time.Second, logger, simplevisor.WithClock(clock))
+//	events := simplevisortest.NewRecorder(t, s)
+//
+//	s.Register("worker", crashingWorker,
+//		simplevisor.WithRestart(simplevisor.RestartOnFailure, 3, time.Minute))
+//	if err := s.Start(); err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	events.Await(t, simplevisor.EventRestarting, "worker", time.Second)
+//	clock.BlockUntil(1)
+//	clock.Advance(time.Minute)
+//
+//	events.Await(t, simplevisor.EventStarted, "worker", time.Second)
+//	simplevisortest.AssertRestartCount(t, s, "worker", 1)
+//
+// Helpers wait in real time for the supervisor goroutines; only the
+// supervisor itself runs on the fake clock.
+package simplevisortest
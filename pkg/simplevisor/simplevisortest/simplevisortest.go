@@ -0,0 +1,136 @@
+package simplevisortest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hasnpr/gohabit/pkg/simplevisor"
+)
+
+// pollInterval is the interval helpers poll the supervisor state
+const pollInterval = 5 * time.Millisecond
+
+// AwaitStatus waits until a process reaches status or fails the test after timeout.
+func AwaitStatus(t testing.TB, s *simplevisor.Supervisor, name string, status simplevisor.ProcessStatus,
+	timeout time.Duration,
+) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := s.GetProcessStatus(name)
+		if err == nil && current == status {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("process %s did not reach status %s within %v, last status %s (err: %v)",
+				name, status, timeout, current, err)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// AssertRestartCount fails the test if the restart count of a process isn't expected.
+func AssertRestartCount(t testing.TB, s *simplevisor.Supervisor, name string, expected int) {
+	t.Helper()
+
+	info, err := s.GetProcessInfo(name)
+	if err != nil {
+		t.Fatalf("failed to get process %s: %v", name, err)
+	}
+
+	if info.RestartCount != expected {
+		t.Errorf("expected restart count %d for process %s, got %d", expected, name, info.RestartCount)
+	}
+}
+
+// Recorder records all events of a supervisor for assertions.
+type Recorder struct {
+	lock    sync.Mutex
+	events  []simplevisor.Event
+	cursor  int           // Index after the last event returned by Await
+	changed chan struct{} // Closed and replaced whenever an event is recorded
+}
+
+// NewRecorder subscribes to the events of s until the test ends.
+func NewRecorder(t testing.TB, s *simplevisor.Supervisor) *Recorder {
+	t.Helper()
+
+	events, unsubscribe := s.Subscribe(1024)
+	r := &Recorder{changed: make(chan struct{})}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range events {
+			r.lock.Lock()
+			r.events = append(r.events, event)
+			close(r.changed)
+			r.changed = make(chan struct{})
+			r.lock.Unlock()
+		}
+	}()
+
+	t.Cleanup(func() {
+		unsubscribe()
+		<-done
+	})
+
+	return r
+}
+
+// Events returns all recorded events.
+func (r *Recorder) Events() []simplevisor.Event {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]simplevisor.Event(nil), r.events...)
+}
+
+// Count returns the number of recorded events of a type for a process.
+func (r *Recorder) Count(eventType simplevisor.EventType, name string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	count := 0
+	for _, event := range r.events {
+		if event.Type == eventType && event.Process == name {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Await waits for the next event of a type for a process, after the events returned by earlier calls.
+// It fails the test after timeout.
+func (r *Recorder) Await(t testing.TB, eventType simplevisor.EventType, name string,
+	timeout time.Duration,
+) simplevisor.Event {
+	t.Helper()
+
+	deadline := time.After(timeout)
+	for {
+		r.lock.Lock()
+		for i := r.cursor; i < len(r.events); i++ {
+			if r.events[i].Type == eventType && r.events[i].Process == name {
+				r.cursor = i + 1
+				event := r.events[i]
+				r.lock.Unlock()
+
+				return event
+			}
+		}
+		changed := r.changed
+		r.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline:
+			t.Fatalf("event %s for process %s not received within %v", eventType, name, timeout)
+			return simplevisor.Event{}
+		}
+	}
+}
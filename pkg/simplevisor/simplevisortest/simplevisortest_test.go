@@ -0,0 +1,175 @@
+package simplevisortest_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hasnpr/gohabit/pkg/simplevisor"
+	"github.com/hasnpr/gohabit/pkg/simplevisor/simplevisortest"
+)
+
+func newSupervisor(clock simplevisor.Clock) *simplevisor.Supervisor {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	return simplevisor.New(time.Second, logger, simplevisor.WithClock(clock))
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := simplevisortest.NewFakeClock(start)
+
+	after := clock.After(time.Minute)
+	var fired atomic.Bool
+	timer := clock.AfterFunc(time.Hour, func() { fired.Store(true) })
+
+	if clock.Timers() != 2 {
+		t.Fatalf("Expected 2 pending timers, got %d", clock.Timers())
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case <-after:
+		t.Fatal("Timer should not fire before its deadline")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case now := <-after:
+		if !now.Equal(start.Add(time.Minute)) {
+			t.Errorf("Expected %v, got %v", start.Add(time.Minute), now)
+		}
+	default:
+		t.Fatal("Timer should fire at its deadline")
+	}
+
+	if !timer.Stop() {
+		t.Error("Stop should report a pending timer")
+	}
+
+	clock.Advance(time.Hour)
+	if fired.Load() {
+		t.Error("Stopped timer should not fire")
+	}
+
+	if clock.Since(start) != time.Hour+time.Minute {
+		t.Errorf("Unexpected elapsed time %v", clock.Since(start))
+	}
+}
+
+func TestDeterministicRestarts(t *testing.T) {
+	clock := simplevisortest.NewFakeClock(time.Now())
+	s := newSupervisor(clock)
+	events := simplevisortest.NewRecorder(t, s)
+
+	var execCount atomic.Int32
+	s.Register("worker", func(ctx context.Context) error {
+		execCount.Add(1)
+		return errors.New("crash")
+	}, simplevisor.WithRestart(simplevisor.RestartOnFailure, 2, time.Hour))
+	s.Run()
+
+	events.Await(t, simplevisor.EventRestarting, "worker", time.Second)
+	simplevisortest.AwaitStatus(t, s, "worker", simplevisor.StatusRestarting, time.Second)
+	simplevisortest.AssertRestartCount(t, s, "worker", 1)
+
+	// Nothing happens until the restart delay elapses on the fake clock
+	clock.BlockUntil(1)
+	if execCount.Load() != 1 {
+		t.Fatalf("Expected 1 execution before the delay elapsed, got %d", execCount.Load())
+	}
+
+	clock.Advance(time.Hour)
+
+	event := events.Await(t, simplevisor.EventRestartLimitExceeded, "worker", time.Second)
+	if event.RestartCount != 2 {
+		t.Errorf("Expected restart count 2, got %d", event.RestartCount)
+	}
+
+	simplevisortest.AwaitStatus(t, s, "worker", simplevisor.StatusStopped, time.Second)
+	simplevisortest.AssertRestartCount(t, s, "worker", 2)
+
+	if events.Count(simplevisor.EventStarted, "worker") != 2 {
+		t.Errorf("Expected 2 starts, got %d", events.Count(simplevisor.EventStarted, "worker"))
+	}
+
+	s.Shutdown()
+}
+
+func TestDeterministicCircuitBreakerCloses(t *testing.T) {
+	clock := simplevisortest.NewFakeClock(time.Now())
+	s := newSupervisor(clock)
+	events := simplevisortest.NewRecorder(t, s)
+
+	var execCount atomic.Int32
+	s.Register("worker", func(ctx context.Context) error {
+		if execCount.Add(1) <= 2 {
+			return errors.New("database unavailable")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}, simplevisor.WithRestart(simplevisor.RestartOnFailure, 2, time.Minute),
+		simplevisor.WithCircuitBreaker(time.Hour, 0))
+	s.Run()
+	defer s.Shutdown()
+
+	// The restart delay after the first failure
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	events.Await(t, simplevisor.EventBreakerOpened, "worker", time.Second)
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	// The half-open run keeps running until it is healthy
+	simplevisortest.AwaitStatus(t, s, "worker", simplevisor.StatusRunning, time.Second)
+	clock.BlockUntil(1)
+	if info, _ := s.GetProcessInfo("worker"); info.Breaker != simplevisor.BreakerHalfOpen {
+		t.Fatalf("Expected half-open breaker during the probe, got %s", info.Breaker)
+	}
+
+	clock.Advance(simplevisor.DefaultHealthyDuration)
+
+	events.Await(t, simplevisor.EventBreakerClosed, "worker", time.Second)
+	simplevisortest.AssertRestartCount(t, s, "worker", 0)
+
+	info, _ := s.GetProcessInfo("worker")
+	if info.Breaker != simplevisor.BreakerClosed || info.Status != simplevisor.StatusRunning {
+		t.Errorf("Expected running process with closed breaker, got %s/%s", info.Status, info.Breaker)
+	}
+	if execCount.Load() != 3 {
+		t.Errorf("Expected 3 executions, got %d", execCount.Load())
+	}
+}
+
+func TestDeterministicShutdownTimeout(t *testing.T) {
+	clock := simplevisortest.NewFakeClock(time.Now())
+	s := newSupervisor(clock)
+	events := simplevisortest.NewRecorder(t, s)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	s.Register("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	s.Run()
+	simplevisortest.AwaitStatus(t, s, "stuck", simplevisor.StatusRunning, time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		s.Shutdown()
+		close(done)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	<-done
+	events.Await(t, simplevisor.EventShutdownTimeout, "", time.Second)
+}
@@ -0,0 +1,182 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"runtime/pprof"
+	"sync"
+)
+
+// PprofLabelChild is the pprof label holding the name of a child goroutine, see Spawn
+const PprofLabelChild = "child"
+
+// errRunFinished cancels the children of a run which has returned
+var errRunFinished = errors.New("process run finished")
+
+// Spawn starts fn in a child goroutine of the current process run.
+// Unlike a plain go statement, the child is supervised with the run:
+//   - A panic or error of the child fails the run and cancels its context. Errors returned after
+//     the context is done aren't failures.
+//   - The run is finished only when all of its children have exited. Once the process returns,
+//     the context of its children is cancelled and the supervisor waits for them, also on shutdown.
+//
+// Outside a supervised process run, or once the run has finished, fn runs untracked and
+// its panic or error is only logged.
+func Spawn(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	run, ok := runInfoFrom(ctx)
+	if !ok || run.children == nil || !run.children.add() {
+		go runUntracked(ctx, name, fn)
+		return
+	}
+
+	go run.children.run(ctx, name, fn)
+}
+
+func runUntracked(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	logger := Logger(ctx).With(slog.String("child", name))
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("recover from panic in untracked goroutine", slog.Any("panic", r))
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		logger.Error("untracked goroutine failed", slog.String("error", err.Error()))
+	}
+}
+
+// childGroup tracks the child goroutines of a process run.
+type childGroup struct {
+	s              *Supervisor
+	process        string
+	recoverHandler RecoverFunc
+	cancel         context.CancelCauseFunc // Cancels the run
+
+	lock    sync.Mutex
+	active  int
+	waiting bool          // The run has returned
+	idle    chan struct{} // Closed once the run has returned and all children exited
+	err     error         // First failure of a child
+}
+
+func (s *Supervisor) newChildGroup(process string, recoverHandler RecoverFunc,
+	cancel context.CancelCauseFunc,
+) *childGroup {
+	return &childGroup{
+		s:              s,
+		process:        process,
+		recoverHandler: recoverHandler,
+		cancel:         cancel,
+		idle:           make(chan struct{}),
+	}
+}
+
+// add counts a new child, it reports false if the run has finished.
+func (g *childGroup) add() bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.waiting && g.active == 0 {
+		return false
+	}
+
+	g.active++
+	g.s.addChildren(g.process, 1)
+
+	return true
+}
+
+func (g *childGroup) run(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	var err error
+	defer func() {
+		g.done(ctx, name, err)
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+			g.s.metrics.recordProcessPanic(g.process)
+			if g.recoverHandler != nil {
+				g.recoverHandler(r)
+			}
+		}
+	}()
+
+	pprof.Do(ctx, pprof.Labels(PprofLabelChild, name), func(ctx context.Context) {
+		err = fn(ctx)
+	})
+}
+
+// done uncounts a child and fails the run if the child failed first.
+func (g *childGroup) done(ctx context.Context, name string, err error) {
+	var panicErr *PanicError
+	failed := err != nil && (ctx.Err() == nil || errors.As(err, &panicErr))
+	if failed {
+		g.s.logger.Error("child goroutine failed",
+			slog.String("process_name", g.process),
+			slog.String("child", name),
+			slog.String("error", err.Error()))
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if failed && g.err == nil {
+		g.err = fmt.Errorf("child %s: %w", name, err)
+		g.cancel(g.err)
+	}
+
+	g.active--
+	g.s.addChildren(g.process, -1)
+	if g.waiting && g.active == 0 {
+		close(g.idle)
+	}
+}
+
+// wait cancels the children of a returned run and waits for them to exit.
+// It returns the first failure of a child.
+func (g *childGroup) wait() error {
+	g.cancel(errRunFinished)
+
+	g.lock.Lock()
+	g.waiting = true
+	if g.active == 0 {
+		close(g.idle)
+	}
+	g.lock.Unlock()
+
+	<-g.idle
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.err
+}
+
+// waitChildren waits for the children of a returned run and merges their failure into the run error.
+func (s *Supervisor) waitChildren(ctx context.Context, run runInfo, processErr error) error {
+	childErr := run.children.wait()
+	if childErr == nil {
+		return processErr
+	}
+
+	// The process most likely returned because the failed child cancelled it
+	if processErr == nil || errors.Is(context.Cause(ctx), childErr) {
+		return childErr
+	}
+
+	return errors.Join(processErr, childErr)
+}
+
+// addChildren adds delta to the child count of a process.
+func (s *Supervisor) addChildren(name string, delta int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if process, exists := s.processes[name]; exists {
+		process.children += delta
+		s.processes[name] = process
+	}
+}
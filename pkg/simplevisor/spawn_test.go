@@ -0,0 +1,161 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForExits waits until a process has at least n exits in its history.
+func waitForExits(t *testing.T, s *Supervisor, name string, n int) []ExitRecord {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if info, err := s.GetProcessInfo(name); err == nil && len(info.Exits) >= n {
+			return info.Exits
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("process %s did not exit %d times", name, n)
+	return nil
+}
+
+func TestSpawn_ChildPanicFailsRun(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var recovered atomic.Value
+	s.Register("parent", func(ctx context.Context) error {
+		Spawn(ctx, "crasher", func(ctx context.Context) error {
+			panic("boom")
+		})
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithRecover(func(r any) {
+		recovered.Store(r)
+	}))
+	s.Run()
+	defer s.Shutdown()
+
+	exits := waitForExits(t, s, "parent", 1)
+
+	var panicErr *PanicError
+	if !errors.As(exits[0].Err, &panicErr) || exits[0].Panic != "boom" {
+		t.Errorf("expected run to fail with the panic of the child, got %v", exits[0].Err)
+	}
+	if recovered.Load() != "boom" {
+		t.Errorf("expected recover handler to be called, got %v", recovered.Load())
+	}
+}
+
+func TestSpawn_ChildErrorFailsRun(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	childErr := errors.New("child failed")
+	s.Register("parent", func(ctx context.Context) error {
+		Spawn(ctx, "failing", func(ctx context.Context) error {
+			return childErr
+		})
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	exits := waitForExits(t, s, "parent", 1)
+	if !errors.Is(exits[0].Err, childErr) || errors.Is(exits[0].Err, context.Canceled) {
+		t.Errorf("expected run to fail with the child error only, got %v", exits[0].Err)
+	}
+}
+
+func TestSpawn_RunWaitsForChildren(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var childExited atomic.Bool
+	s.Register("parent", func(ctx context.Context) error {
+		Spawn(ctx, "slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			childExited.Store(true)
+			return ctx.Err()
+		})
+		return nil
+	})
+	s.Run()
+	defer s.Shutdown()
+
+	exits := waitForExits(t, s, "parent", 1)
+	if !childExited.Load() {
+		t.Error("expected run to finish after its child exited")
+	}
+	if exits[0].Err != nil {
+		t.Errorf("expected child cancelled by the finished run not to fail it, got %v", exits[0].Err)
+	}
+}
+
+func TestSpawn_ChildrenInStatusAndShutdown(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	var exited atomic.Int32
+	s.Register("parent", func(ctx context.Context) error {
+		for _, name := range []string{"a", "b"} {
+			Spawn(ctx, name, func(ctx context.Context) error {
+				// Nested children are tracked too
+				Spawn(ctx, name+"-nested", func(ctx context.Context) error {
+					<-ctx.Done()
+					exited.Add(1)
+					return nil
+				})
+				<-ctx.Done()
+				exited.Add(1)
+				return nil
+			})
+		}
+		<-ctx.Done()
+		return nil
+	})
+	s.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if info, _ := s.GetProcessInfo("parent"); info.Children == 4 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if info, _ := s.GetProcessInfo("parent"); info.Children != 4 {
+		t.Errorf("expected 4 children in status, got %d", info.Children)
+	}
+	for _, process := range s.controlProcesses() {
+		if process.Children != 4 {
+			t.Errorf("expected 4 children in control status, got %d", process.Children)
+		}
+	}
+
+	s.Shutdown()
+
+	if exited.Load() != 4 {
+		t.Errorf("expected shutdown to wait for all children, %d exited", exited.Load())
+	}
+	if info, _ := s.GetProcessInfo("parent"); info.Children != 0 {
+		t.Errorf("expected no children after shutdown, got %d", info.Children)
+	}
+}
+
+func TestSpawn_OutsideProcess(t *testing.T) {
+	done := make(chan struct{})
+	Spawn(context.Background(), "untracked", func(ctx context.Context) error {
+		defer close(done)
+		panic("recovered")
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected untracked goroutine to run")
+	}
+}
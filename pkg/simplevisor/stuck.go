@@ -0,0 +1,166 @@
+package simplevisor
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stuckProcess is a process or job which didn't exit within the shutdown timeout.
+type stuckProcess struct {
+	name       string
+	kind       string // process or job
+	status     string
+	runningFor time.Duration
+	goroutine  uint64
+}
+
+// WithStuckGoroutineDump attaches the stack of every process which doesn't exit
+// within the shutdown timeout to its shutdown warning.
+func WithStuckGoroutineDump() SupervisorOption {
+	return func(s *Supervisor) {
+		s.dumpStuckGoroutines = true
+	}
+}
+
+// reportStuckProcesses logs which processes and jobs are still running after the shutdown timeout.
+func (s *Supervisor) reportStuckProcesses() {
+	stuck := s.stuckProcesses()
+
+	names := make([]string, 0, len(stuck))
+	for _, p := range stuck {
+		names = append(names, p.name)
+	}
+
+	s.logger.Warn("shutdown timeout exceeded, some processes are still running",
+		slog.Any("processes", names))
+	s.metrics.recordShutdownTimeout()
+	s.publish(Event{
+		Type: EventShutdownTimeout,
+		Err:  fmt.Errorf("processes did not exit within %v: %s", s.shutdownTimeout, strings.Join(names, ", ")),
+	})
+
+	var dumps map[uint64]string
+	if s.dumpStuckGoroutines {
+		dumps = goroutineDumps()
+	}
+
+	for _, p := range stuck {
+		attrs := []any{
+			slog.String("process_name", p.name),
+			slog.String("kind", p.kind),
+			slog.String("status", p.status),
+			slog.Duration("running_for", p.runningFor),
+		}
+		if dump, ok := dumps[p.goroutine]; ok {
+			attrs = append(attrs, slog.String("goroutine_dump", dump))
+		}
+
+		s.logger.Warn("process did not exit within shutdown timeout", attrs...)
+	}
+}
+
+// stuckProcesses returns the processes and jobs whose goroutines are still alive, sorted by name.
+func (s *Supervisor) stuckProcesses() []stuckProcess {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var stuck []stuckProcess
+	for name, process := range s.processes {
+		if process.done == nil || isClosed(process.done) {
+			continue
+		}
+
+		stuck = append(stuck, stuckProcess{
+			name:       name,
+			kind:       "process",
+			status:     process.status.String(),
+			runningFor: s.runningFor(process.startedAt),
+			goroutine:  process.goroutine,
+		})
+	}
+
+	for job := range s.jobs {
+		job.lock.Lock()
+		stuck = append(stuck, stuckProcess{
+			name:       job.name,
+			kind:       "job",
+			status:     job.status.String(),
+			runningFor: s.runningFor(job.startedAt),
+			goroutine:  job.goroutine,
+		})
+		job.lock.Unlock()
+	}
+
+	sort.Slice(stuck, func(i, j int) bool {
+		return stuck[i].name < stuck[j].name
+	})
+
+	return stuck
+}
+
+func (s *Supervisor) runningFor(startedAt time.Time) time.Duration {
+	if startedAt.IsZero() {
+		return 0
+	}
+
+	return s.clock.Since(startedAt)
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// goroutineID returns the id of the calling goroutine.
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	// The stack starts with "goroutine <id> [<state>]:"
+	fields := bytes.Fields(bytes.TrimPrefix(buf, []byte("goroutine ")))
+	if len(fields) == 0 {
+		return 0
+	}
+
+	id, _ := strconv.ParseUint(string(fields[0]), 10, 64)
+	return id
+}
+
+// goroutineStacks returns the stacks of all goroutines.
+func goroutineStacks() []byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// goroutineDumps returns the stacks of all goroutines by goroutine id.
+func goroutineDumps() map[uint64]string {
+	dumps := make(map[uint64]string)
+	for _, stack := range strings.Split(string(goroutineStacks()), "\n\n") {
+		fields := strings.Fields(strings.TrimPrefix(stack, "goroutine "))
+		if len(fields) == 0 {
+			continue
+		}
+
+		if id, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+			dumps[id] = stack
+		}
+	}
+
+	return dumps
+}
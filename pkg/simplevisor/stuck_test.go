@@ -0,0 +1,133 @@
+package simplevisor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent log writes
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+// allRecords returns all decoded JSON log records
+func (b *syncBuffer) allRecords(t *testing.T) []map[string]any {
+	t.Helper()
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var records []map[string]any
+	for _, line := range bytes.Split(b.buf.Bytes(), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Failed to decode log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// records returns the decoded JSON log records with the given message
+func (b *syncBuffer) records(t *testing.T, msg string) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, record := range b.allRecords(t) {
+		if record["msg"] == msg {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// messages returns the messages of all log records
+func (b *syncBuffer) messages(t *testing.T) []string {
+	t.Helper()
+
+	var messages []string
+	for _, record := range b.allRecords(t) {
+		messages = append(messages, record["msg"].(string))
+	}
+
+	return messages
+}
+
+func blockForever(release chan struct{}) {
+	<-release
+}
+
+func TestSupervisor_ReportsStuckProcesses(t *testing.T) {
+	logs := &syncBuffer{}
+	s := New(50*time.Millisecond, slog.New(slog.NewJSONHandler(logs, nil)), WithStuckGoroutineDump())
+
+	release := make(chan struct{})
+	defer close(release)
+
+	s.Register("stuck-process", func(ctx context.Context) error {
+		blockForever(release)
+		return nil
+	})
+	s.Register("healthy-process", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	job := s.Submit("stuck-job", func(ctx context.Context) error {
+		blockForever(release)
+		return nil
+	})
+	s.Run()
+
+	waitForStatus(t, s, "stuck-process", StatusRunning, time.Second)
+	waitForStatus(t, s, "healthy-process", StatusRunning, time.Second)
+	for job.Status() != JobRunning {
+		time.Sleep(time.Millisecond)
+	}
+
+	s.Shutdown()
+
+	summary := logs.records(t, "shutdown timeout exceeded, some processes are still running")
+	if len(summary) != 1 {
+		t.Fatalf("Expected one summary warning, got %d", len(summary))
+	}
+
+	group := summary[0][LogNSSupervisor].(map[string]any)
+	names, _ := group["processes"].([]any)
+	if len(names) != 2 || names[0] != "stuck-job" || names[1] != "stuck-process" {
+		t.Errorf("Expected stuck-job and stuck-process to be reported, got %v", group["processes"])
+	}
+
+	warnings := logs.records(t, "process did not exit within shutdown timeout")
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 process warnings, got %d", len(warnings))
+	}
+
+	for _, warning := range warnings {
+		attrs := warning[LogNSSupervisor].(map[string]any)
+		dump, _ := attrs["goroutine_dump"].(string)
+		if !bytes.Contains([]byte(dump), []byte("blockForever")) {
+			t.Errorf("Goroutine dump of %v should contain the blocking call, got %q", attrs["process_name"], dump)
+		}
+
+		if _, ok := attrs["running_for"]; !ok {
+			t.Errorf("Warning of %v should report the running duration", attrs["process_name"])
+		}
+	}
+}
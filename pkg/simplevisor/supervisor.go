@@ -8,12 +8,17 @@ package simplevisor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"maps"
 	"os"
 	"os/signal"
+	"runtime/debug"
+	"runtime/pprof"
+	"slices"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -43,6 +48,8 @@ const (
 	StatusStopped ProcessStatus = iota
 	StatusRunning
 	StatusRestarting
+	StatusStandby // Waiting to acquire leadership
+	StatusPaused  // Paused until Resume
 )
 
 // ProcessFunc is a long-running process which listens on context cancellation.
@@ -51,22 +58,56 @@ type ProcessFunc func(ctx context.Context) error
 // RecoverFunc is a function to execute when a process panics.
 type RecoverFunc func(r any)
 
+// PanicError is returned in place of a process error when the process panics.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
 // Supervisor is responsible to manage long-running processes.
 // Supervisor is not for concurrent use and should be used as the main goroutine of app.
 type Supervisor struct {
 	shutDownCtx     context.Context
 	shutDownCancel  context.CancelFunc
 	logger          *slog.Logger
+	processLogger   *slog.Logger // Base of the loggers passed to processes, see Logger
 	lock            sync.Mutex
 	processes       map[string]Process
+	pools           map[string]pool
+	running         bool // Run has been called
 	shutdownSignal  chan os.Signal
 	shutdownTimeout time.Duration
 	processWg       sync.WaitGroup  // Tracks running process goroutines
 	metrics         metricsRecorder // Metrics recorder (NoOp by default, OpenTelemetry when enabled)
+	clock           Clock
+	events          eventBus
+	jobs            map[*JobHandle]struct{} // In-flight jobs
+	processConfigs  map[string]ProcessConfig
+	draining        chan struct{} // Closed at the start of shutdown
+	shutdownBy      time.Time     // Deadline of the shutdown once started, see commandGracePeriod
+	drainPeriod     time.Duration
+	middlewares     []Middleware // Supervisor chain, see Use
+	controlSocket   string       // Path of the control socket, see WithControlSocket
+	logDedupWindow  time.Duration
+	logLevels       map[string]slog.Level // Per-process log levels
+	crashReports    *crashReports         // Nil unless WithCrashReports
+	notifiers       []Notifier
+	notifications   *notifications // Nil without notifiers
+	leakCheckGrace  time.Duration  // Zero disables the leak check, see WithGoroutineLeakCheck
+	chaos           *chaos         // Nil unless WithChaos
+
+	dumpStuckGoroutines bool
 }
 
+// SupervisorOption configures a Supervisor in New.
+type SupervisorOption func(s *Supervisor)
+
 // New returns new instance of Supervisor.
-func New(shutdownTimeout time.Duration, sLog *slog.Logger) *Supervisor {
+func New(shutdownTimeout time.Duration, sLog *slog.Logger, options ...SupervisorOption) *Supervisor {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	if sLog == nil {
@@ -77,16 +118,32 @@ func New(shutdownTimeout time.Duration, sLog *slog.Logger) *Supervisor {
 		shutdownTimeout = DefaultGracefulShutdownTimeout
 	}
 
-	return &Supervisor{
+	s := &Supervisor{
 		shutDownCtx:     ctx,
 		shutDownCancel:  cancel,
 		lock:            sync.Mutex{},
 		logger:          sLog.WithGroup(LogNSSupervisor),
+		processLogger:   sLog,
 		processes:       make(map[string]Process),
+		pools:           make(map[string]pool),
+		jobs:            make(map[*JobHandle]struct{}),
 		shutdownSignal:  make(chan os.Signal, 1),
 		shutdownTimeout: shutdownTimeout,
 		metrics:         &noOpMetrics{}, // Default to NoOp metrics to avoid nil pointer issues
+		clock:           realClock{},
+		draining:        make(chan struct{}),
 	}
+
+	s.middlewares = s.defaultMiddlewares()
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.setupLogging(sLog)
+	s.startNotifications()
+
+	return s
 }
 
 // EnableMetrics initializes OpenTelemetry metrics for the supervisor.
@@ -97,20 +154,50 @@ func (s *Supervisor) EnableMetrics() error {
 		return fmt.Errorf("failed to initialize metrics: %w", err)
 	}
 	s.metrics = metrics
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for name, process := range s.processes {
+		metrics.setProcessLabels(name, process.labels)
+	}
+
 	return nil
 }
 
 type Option func(p *Process)
 
 type Process struct {
-	name           string
-	handler        ProcessFunc
-	recoverHandler RecoverFunc
-	restartPolicy  RestartPolicy
-	maxRestarts    int
-	restartDelay   time.Duration
-	restartCount   int
-	status         ProcessStatus
+	name             string
+	handler          ProcessFunc
+	recoverHandler   RecoverFunc
+	restartPolicy    RestartPolicy
+	maxRestarts      int
+	restartDelay     time.Duration
+	restartCount     int
+	status           ProcessStatus
+	pool             string // Name of the pool the process is a replica of
+	replica          int    // Replica index inside the pool
+	removed          bool   // Process is scaled down and leaves once it exits
+	breaker          BreakerState
+	breakerCoolOff   time.Duration // Zero disables the circuit breaker
+	breakerMaxProbes int
+	probeFailures    int
+	locker           Locker             // Nil unless the process is leader-elected
+	cancel           context.CancelFunc // Cancels the running process goroutine
+	done             chan struct{}      // Closed when the process goroutine exits
+	startedAt        time.Time          // Start of the current run
+	attempts         int                // Number of runs so far
+	goroutine        uint64             // Id of the process goroutine
+	labels           map[string]string
+	stopped          bool // Stopped by StopGroup, not started until RestartGroup
+	paused           bool // Paused, not started until Resume
+	middlewares      []Middleware
+	exitHistorySize  int
+	exits            []ExitRecord // Last exits, oldest first
+	maxRuntime       time.Duration
+	startupTimeout   time.Duration
+	ready            bool // The current run signalled readiness
+	children         int  // Live child goroutines of the current run, see Spawn
 }
 
 // WithRecover sets the recover handler for the process.
@@ -137,39 +224,97 @@ func WithRestart(policy RestartPolicy, maxRestarts int, delay time.Duration) Opt
 func (s *Supervisor) Register(name string, handler ProcessFunc, options ...Option) {
 	s.panicIfNameAlreadyInUse(name)
 
-	process := Process{
-		name:         name,
-		handler:      handler,
-		maxRestarts:  DefaultMaxRestarts,
-		restartDelay: DefaultRestartDelay,
-		status:       StatusStopped,
-	}
-
-	for _, option := range options {
-		option(&process)
-	}
+	process := newProcess(name, handler, options...)
+	s.applyConfig(&process, name)
 
 	s.lock.Lock()
 	s.processes[name] = process
 	s.lock.Unlock()
+	s.metrics.setProcessLabels(name, process.labels)
 
 	// Update total processes metric - increment stopped processes
 	s.metrics.updateTotalProcesses(1, StatusStopped)
 }
 
+// newProcess builds a process with default settings and applies the options.
+func newProcess(name string, handler ProcessFunc, options ...Option) Process {
+	process := Process{
+		name:            name,
+		handler:         handler,
+		maxRestarts:     DefaultMaxRestarts,
+		restartDelay:    DefaultRestartDelay,
+		status:          StatusStopped,
+		exitHistorySize: DefaultExitHistorySize,
+	}
+
+	for _, option := range options {
+		option(&process)
+	}
+
+	return process
+}
+
 // Run spawns a new goroutine for each process.
 // Spawned goroutine is responsible to handle the panic.
+// It starts no process and logs an error if the config is invalid, see Start.
 func (s *Supervisor) Run() {
+	if err := s.Start(); err != nil {
+		s.logger.Error("failed to run supervisor", slog.String("error", err.Error()))
+	}
+}
+
+// Start is Run, except that it returns an error without starting any process if the config given
+// to NewFromConfig has settings for unknown processes, see CheckConfig; register all processes before.
+// It returns ErrDraining once shutdown started.
+func (s *Supervisor) Start() error {
+	if err := s.CheckConfig(); err != nil {
+		return err
+	}
+
 	s.lock.Lock()
-	processes := make(map[string]Process, len(s.processes))
-	maps.Copy(processes, s.processes)
-	s.lock.Unlock()
+	if s.running {
+		s.lock.Unlock()
+		return nil
+	}
+	if isClosed(s.draining) {
+		s.lock.Unlock()
+		return ErrDraining
+	}
+	s.running = true
 
 	// there is no need to use a goroutine pool such as Ants because this goroutine is long-running.
-	for name, p := range processes {
-		s.processWg.Add(1)
-		go s.executeProcessWithRestart(name, p)
+	for name, process := range s.processes {
+		if process.stopped || process.paused {
+			continue
+		}
+		s.spawnLocked(name)
+	}
+	s.lock.Unlock()
+
+	// Socket setup does file system calls, keep it out of the lock
+	if s.controlSocket != "" {
+		s.listenControl()
 	}
+
+	return nil
+}
+
+// spawnLocked starts the goroutine of a registered process with its own cancellable context.
+// The caller must hold s.lock.
+func (s *Supervisor) spawnLocked(name string) {
+	process := s.processes[name]
+
+	ctx, cancel := context.WithCancel(s.shutDownCtx)
+	if process.pool != "" {
+		ctx = context.WithValue(ctx, replicaIndexKey, process.replica)
+	}
+
+	process.cancel = cancel
+	process.done = make(chan struct{})
+	s.processes[name] = process
+
+	s.processWg.Add(1)
+	go s.executeProcessWithRestart(ctx, name, process)
 }
 
 func (s *Supervisor) Context() context.Context {
@@ -201,43 +346,98 @@ func (s *Supervisor) shutdown(teardown func()) {
 	s.shutDownCancel()
 }
 
-func (s *Supervisor) executeProcessWithRestart(name string, process Process) {
+func (s *Supervisor) executeProcessWithRestart(ctx context.Context, name string, process Process) {
 	defer s.processWg.Done()
+	defer close(process.done)
+	defer s.leaveIfRemoved(name, process.done)
+	defer s.setExitStatus(name)
+	defer process.cancel()
+
+	s.setGoroutine(name, goroutineID())
 
 	for {
 		select {
-		case <-s.shutDownCtx.Done():
+		case <-ctx.Done():
+			return
+		case <-s.draining:
 			return
 		default:
 		}
 
-		startTime := time.Now()
-		shouldRestart := s.executeProcess(name, process)
+		if !s.delayStart(ctx, process) {
+			return
+		}
+
+		lease, ok := s.acquireLease(ctx, name, process)
+		if !ok {
+			return
+		}
+
+		runCtx, stopLease := leaseContext(ctx, lease)
+		startTime := s.clock.Now()
+		stopProbe := s.startBreakerProbe(name)
+		cleanups := &cleanupStack{}
+		shouldRestart, processErr := s.executeProcess(runCtx, name, process, cleanups)
+		stopProbe()
+		stopLease()
+		s.recordExit(name, startTime, processErr, cleanups.cleanupErr())
+
+		// Losing the lease is not a failure of the process; wait to become the leader again
+		if s.releaseLease(name, lease) {
+			continue
+		}
+
+		// Stopped on purpose, e.g. paused or shut down; not a failure of the process
+		if ctx.Err() != nil || isClosed(s.draining) {
+			return
+		}
 
 		if !shouldRestart {
+			s.closeBreaker(name)
 			s.setProcessStatus(name, StatusStopped)
 			return
 		}
 
+		// A half-open run which is still not closed failed before it became healthy
+		if s.getBreakerState(name) == BreakerHalfOpen {
+			if !s.reopenBreaker(ctx, name, process) {
+				return
+			}
+			s.markRestarted(name)
+			continue
+		}
+
 		// Check if process ran long enough to be considered healthy
-		runDuration := time.Since(startTime)
-		if runDuration >= DefaultHealthyDuration {
+		runDuration := s.clock.Since(startTime)
+		switch {
+		case runDuration >= DefaultHealthyDuration:
 			s.logger.Info("process ran successfully for healthy duration, resetting restart count",
 				slog.String("process_name", name),
 				slog.Duration("run_duration", runDuration),
 				slog.Int("previous_restart_count", s.getRestartCount(name)))
 			s.resetRestartCount(name)
-		} else {
+		case errors.Is(processErr, ErrMaxRuntimeExceeded):
+			// A recycled run is not a failure and keeps the restart budget
+		default:
 			// Only increment restart count if process didn't run long enough
 			s.incrementRestartCount(name)
 		}
 
 		// Check if we've exceeded max restarts
 		if process.maxRestarts > 0 && s.getRestartCount(name) >= process.maxRestarts {
+			if process.breakerCoolOff > 0 {
+				if !s.openBreaker(ctx, name, process) {
+					return
+				}
+				s.markRestarted(name)
+				continue
+			}
+
 			s.logger.Error("process exceeded max restarts",
 				slog.String("process_name", name),
 				slog.Int("restart_count", s.getRestartCount(name)))
 			s.metrics.recordRestartLimitExceeded(name, process.maxRestarts)
+			s.publish(Event{Type: EventRestartLimitExceeded, Process: name, RestartCount: s.getRestartCount(name)})
 			s.setProcessStatus(name, StatusStopped)
 			return
 		}
@@ -249,75 +449,134 @@ func (s *Supervisor) executeProcessWithRestart(name string, process Process) {
 			slog.Duration("delay", process.restartDelay),
 			slog.Int("restart_count", restartCount))
 		s.metrics.recordProcessRestarted(name, process.restartPolicy, restartCount)
+		s.publish(Event{Type: EventRestarting, Process: name, RestartCount: restartCount})
 
 		// Wait for restart delay or shutdown signal
 		select {
-		case <-time.After(process.restartDelay):
-		case <-s.shutDownCtx.Done():
+		case <-s.clock.After(process.restartDelay):
+		case <-ctx.Done():
+			return
+		case <-s.draining:
 			return
 		}
+		s.markRestarted(name)
 	}
 }
 
-func (s *Supervisor) executeProcess(name string, process Process) bool {
-	var processErr error
-	var panicOccurred bool
+func (s *Supervisor) executeProcess(ctx context.Context, name string, process Process,
+	cleanups *cleanupStack,
+) (bool, error) {
+	run := s.markStarted(name)
+	run.cleanups = cleanups
+	ctx, finish := s.limitRun(ctx, process, &run)
+	processErr := finish(s.runProcess(ctx, process, run))
+
+	// Determine if we should restart based on policy
+	switch process.restartPolicy {
+	case RestartNever:
+		return false, processErr
+	case RestartAlways:
+		return true, processErr
+	case RestartOnFailure:
+		return processErr != nil, processErr
+	default:
+		return false, processErr
+	}
+}
+
+// runProcess runs the process handler once, wrapped by the supervisor and process middlewares.
+// A panic is recovered and returned as a *PanicError.
+func (s *Supervisor) runProcess(ctx context.Context, process Process, run runInfo) (processErr error) {
+	name := run.name
+	run.logger = s.processLogger.With(slog.String("process_name", name), slog.Int("attempt", run.attempt))
+	run.draining = s.draining
+	run.restartPolicy = process.restartPolicy
+	run.recoverHandler = process.recoverHandler
+	run.labels = process.labels
+	if run.cleanups == nil {
+		run.cleanups = &cleanupStack{}
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	run.children = s.newChildGroup(name, process.recoverHandler, cancel)
+	ctx = context.WithValue(ctx, runInfoKey, run)
 
 	defer func() {
+		// Safety net for a middleware chain without RecoverMiddleware
 		if r := recover(); r != nil {
-			panicOccurred = true
+			processErr = &PanicError{Value: r, Stack: debug.Stack()}
 			s.logger.Error("recover from panic", slog.String("process_name", name), slog.Any("panic", r))
-			s.metrics.recordProcessPanic(name)
+		}
 
-			if process.recoverHandler != nil {
-				process.recoverHandler(r)
-			}
+		processErr = s.waitChildren(ctx, run, processErr)
+		s.runCleanups(run)
+
+		var panicErr *PanicError
+		if errors.As(processErr, &panicErr) {
+			s.reportCrash(run, panicErr)
+			s.publish(Event{Type: EventPanic, Process: name, Err: processErr})
 		}
+		s.publish(Event{Type: EventStopped, Process: name, Err: processErr})
 	}()
 
-	s.logger.Info("execute process", slog.String("process_name", name))
-	s.setProcessStatus(name, StatusRunning)
-	s.metrics.recordProcessStarted(s.shutDownCtx, name, process.restartPolicy)
+	s.publish(Event{Type: EventStarted, Process: name})
 
-	processErr = process.handler(s.shutDownCtx)
-	if processErr != nil {
-		s.logger.Error("process execution finished", slog.String("process_name", name),
-			slog.String("error", processErr.Error()))
-		s.metrics.recordProcessStopped(name, "error")
-	} else {
-		s.metrics.recordProcessStopped(name, "success")
+	if s.leakCheckGrace > 0 {
+		defer func() {
+			s.clock.AfterFunc(s.leakCheckGrace, func() {
+				s.checkGoroutineLeak(name, run.attempt)
+			})
+		}()
 	}
 
-	// Determine if we should restart based on policy
-	switch process.restartPolicy {
-	case RestartNever:
-		return false
-	case RestartAlways:
-		return true
-	case RestartOnFailure:
-		return processErr != nil || panicOccurred
-	default:
-		return false
+	// Goroutines started by the process inherit the labels, see GoroutineCounts
+	middlewares := append(s.getMiddlewares(), process.middlewares...)
+	if s.chaos != nil {
+		middlewares = append(middlewares, s.chaos.middleware(s))
 	}
+	handler := chain(process.handler, middlewares...)
+	labels := pprof.Labels(PprofLabelProcess, name, PprofLabelAttempt, strconv.Itoa(run.attempt))
+	pprof.Do(ctx, labels, func(ctx context.Context) {
+		processErr = handler(ctx)
+	})
+
+	return processErr
 }
 
 func (s *Supervisor) panicIfNameAlreadyInUse(name string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	s.panicIfNameAlreadyInUseLocked(name)
+}
+
+// panicIfNameAlreadyInUseLocked panics if name is used by a process or a pool. The caller must hold s.lock.
+func (s *Supervisor) panicIfNameAlreadyInUseLocked(name string) {
 	if _, ok := s.processes[name]; ok {
 		s.logger.Error("process name already in use", slog.String("process_name", name))
 		panic(fmt.Sprintf("process name %q already in use", name))
 	}
+
+	if _, ok := s.pools[name]; ok {
+		s.logger.Error("pool name already in use", slog.String("pool_name", name))
+		panic(fmt.Sprintf("pool name %q already in use", name))
+	}
 }
 
 func (s *Supervisor) gracefulShutdown() {
 	s.logger.Info("notify all processes to finish their jobs",
 		slog.Duration("shutdown_timeout", s.shutdownTimeout),
-		slog.Int("number_of_processes", len(s.processes)))
+		slog.Int("number_of_processes", s.ProcessCount()))
 
-	// Cancel context to signal all processes to shutdown
-	s.shutDownCancel()
+	deadline := s.clock.Now().Add(s.shutdownTimeout)
+	timeout := s.clock.After(s.shutdownTimeout)
+
+	// Goroutines are only added to processWg under the lock while not draining, so Wait sees all of them
+	s.lock.Lock()
+	if !isClosed(s.draining) {
+		close(s.draining)
+	}
+	s.shutdownBy = deadline
+	s.lock.Unlock()
 
 	// Wait for all process goroutines to finish with timeout
 	done := make(chan struct{})
@@ -326,14 +585,22 @@ func (s *Supervisor) gracefulShutdown() {
 		close(done)
 	}()
 
+	// Let processes finish their in-flight work before their contexts are cancelled
+	s.drain(done)
+
+	// Cancel context to signal all processes to shutdown
+	s.shutDownCancel()
+
 	select {
 	case <-done:
 		s.logger.Info("all processes terminated gracefully")
-	case <-time.After(s.shutdownTimeout):
-		s.logger.Warn("shutdown timeout exceeded, some processes may still be running")
-		s.metrics.recordShutdownTimeout()
+	case <-timeout:
+		s.reportStuckProcesses()
 	}
 
+	// Notifications share the shutdown budget
+	s.flushNotifications(deadline.Sub(s.clock.Now()))
+
 	s.logger.Info("supervisor terminates its job.")
 }
 
@@ -369,11 +636,62 @@ func (s *Supervisor) GetProcessStatus(name string) (ProcessStatus, error) {
 	return process.status, nil
 }
 
+// ProcessInfo is a snapshot of a registered process.
+type ProcessInfo struct {
+	Name         string
+	Status       ProcessStatus
+	RestartCount int
+	Pool         string // Empty if the process is not a pool replica
+	Replica      int    // Replica index inside the pool
+	Breaker      BreakerState
+	Attempts     int       // Number of runs so far
+	StartedAt    time.Time // Start of the current or last run
+	Labels       map[string]string
+	Exits        []ExitRecord // Last exits, oldest first, see WithExitHistory
+	Ready        bool         // The current run signalled readiness, see Ready
+	Children     int          // Live child goroutines of the current run, see Spawn
+}
+
+// GetProcessInfo returns a snapshot of a process
+func (s *Supervisor) GetProcessInfo(name string) (ProcessInfo, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	process, exists := s.processes[name]
+	if !exists {
+		return ProcessInfo{}, fmt.Errorf("process %s not found", name)
+	}
+
+	return process.info(), nil
+}
+
+func (p Process) info() ProcessInfo {
+	return ProcessInfo{
+		Name:         p.name,
+		Status:       p.status,
+		RestartCount: p.restartCount,
+		Pool:         p.pool,
+		Replica:      p.replica,
+		Breaker:      p.breaker,
+		Attempts:     p.attempts,
+		StartedAt:    p.startedAt,
+		Labels:       maps.Clone(p.labels),
+		Exits:        slices.Clone(p.exits),
+		Ready:        p.ready,
+		Children:     p.children,
+	}
+}
+
 // setProcessStatus updates the status of a process
 func (s *Supervisor) setProcessStatus(name string, status ProcessStatus) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	s.setProcessStatusLocked(name, status)
+}
+
+// setProcessStatusLocked updates the status of a process. The caller must hold s.lock.
+func (s *Supervisor) setProcessStatusLocked(name string, status ProcessStatus) {
 	if process, exists := s.processes[name]; exists {
 		oldStatus := process.status
 		process.status = status
@@ -383,10 +701,44 @@ func (s *Supervisor) setProcessStatus(name string, status ProcessStatus) {
 		if oldStatus != status {
 			s.metrics.updateTotalProcesses(-1, oldStatus)
 			s.metrics.updateTotalProcesses(1, status)
+
+			if process.pool != "" {
+				s.updatePoolMetricsLocked(process.pool)
+			}
 		}
 	}
 }
 
+// markStarted sets a process to running and records the start of its run
+func (s *Supervisor) markStarted(name string) runInfo {
+	s.setProcessStatus(name, StatusRunning)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	run := runInfo{name: name, startedAt: s.clock.Now()}
+	if process, exists := s.processes[name]; exists {
+		process.attempts++
+		process.startedAt = run.startedAt
+		process.ready = false
+		s.processes[name] = process
+		run.attempt = process.attempts
+	}
+
+	return run
+}
+
+// setGoroutine records the id of the goroutine running a process
+func (s *Supervisor) setGoroutine(name string, id uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if process, exists := s.processes[name]; exists {
+		process.goroutine = id
+		s.processes[name] = process
+	}
+}
+
 // incrementRestartCount increments the restart counter for a process
 func (s *Supervisor) incrementRestartCount(name string) {
 	s.lock.Lock()
@@ -0,0 +1,51 @@
+package simplevisor
+
+import (
+	"context"
+	"sync"
+)
+
+// Task is a job which produces a value, see Go.
+type Task[T any] struct {
+	*JobHandle
+
+	lock  sync.Mutex
+	value T
+}
+
+// Go runs fn exactly once under supervision like Submit and returns a task holding its value.
+// A failed run is retried according to the RestartPolicy of the task, RestartNever (default) doesn't retry.
+// The context of fn is canceled on shutdown.
+// Go is a function rather than a method because methods can't have type parameters.
+func Go[T any](s *Supervisor, name string, fn func(ctx context.Context) (T, error), options ...Option) *Task[T] {
+	task := &Task[T]{}
+	task.JobHandle = s.Submit(name, func(ctx context.Context) error {
+		value, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+
+		task.lock.Lock()
+		task.value = value
+		task.lock.Unlock()
+
+		return nil
+	}, options...)
+
+	return task
+}
+
+// Await blocks until the task finishes and returns its value and error.
+// The value is the zero value of T if the task failed.
+// If ctx is done first, ctx.Err() is returned and the task keeps running.
+func (t *Task[T]) Await(ctx context.Context) (T, error) {
+	var zero T
+	if err := t.Wait(ctx); err != nil {
+		return zero, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.value, nil
+}
@@ -0,0 +1,116 @@
+package simplevisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGo_ReturnsValue(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	defer s.Shutdown()
+
+	task := Go(s, "answer", func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	value, err := task.Await(ctx)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if value != 42 {
+		t.Errorf("Expected 42, got %d", value)
+	}
+	if task.Status() != JobSucceeded {
+		t.Errorf("Expected JobSucceeded, got %s", task.Status())
+	}
+}
+
+func TestGo_RetriesUnderRestartPolicy(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	defer s.Shutdown()
+
+	var runs atomic.Int32
+	task := Go(s, "flaky", func(ctx context.Context) (string, error) {
+		if runs.Add(1) < 3 {
+			return "partial", errors.New("temporary failure")
+		}
+		return "done", nil
+	}, WithRestart(RestartOnFailure, 3, 10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	value, err := task.Await(ctx)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if value != "done" {
+		t.Errorf("Expected value of the successful run, got %q", value)
+	}
+	if task.Attempts() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", task.Attempts())
+	}
+}
+
+func TestGo_FailureReturnsZeroValue(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	defer s.Shutdown()
+
+	task := Go(s, "failing", func(ctx context.Context) (int, error) {
+		return 1, errors.New("failed")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	value, err := task.Await(ctx)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if value != 0 {
+		t.Errorf("Expected zero value, got %d", value)
+	}
+}
+
+func TestGo_PanicIsRecovered(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+	defer s.Shutdown()
+
+	task := Go(s, "panicking", func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := task.Await(ctx); err == nil {
+		t.Fatal("Expected error from recovered panic, got nil")
+	}
+}
+
+func TestGo_CanceledOnShutdown(t *testing.T) {
+	s := createTestSupervisor(time.Second)
+
+	started := make(chan struct{})
+	task := Go(s, "blocking", func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	<-started
+	s.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := task.Await(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package simplevisor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const (
+	// DefaultWebhookTemplate is a payload for Slack and Mattermost incoming webhooks
+	DefaultWebhookTemplate = `{"text": {{json .Text}}}`
+	// DefaultWebhookMaxRetries is the number of retries of a failed webhook request
+	DefaultWebhookMaxRetries = 3
+	// DefaultWebhookRetryDelay is the delay before the first retry, doubled for every further retry
+	DefaultWebhookRetryDelay = time.Second
+)
+
+// WebhookConfig configures a WebhookNotifier.
+type WebhookConfig struct {
+	URL string
+	// Template renders the JSON request body from a WebhookPayload with text/template.
+	// The json function encodes a value as JSON, e.g. {{json .Text}}. DefaultWebhookTemplate if empty.
+	Template   string
+	Headers    map[string]string
+	MaxRetries int           // DefaultWebhookMaxRetries if 0, no retries if < 0
+	RetryDelay time.Duration // DefaultWebhookRetryDelay if <= 0
+	// RateLimit is the max number of notifications per RatePeriod, unlimited if <= 0.
+	// Notifications over the limit are dropped and counted in the next WebhookPayload.
+	RateLimit  int
+	RatePeriod time.Duration
+	Client     *http.Client // http.DefaultClient if nil
+}
+
+// WebhookPayload is the data of the webhook template.
+type WebhookPayload struct {
+	Event        string // Event type, e.g. panic
+	Process      string
+	Error        string
+	RestartCount int
+	Time         time.Time
+	Hostname     string
+	Suppressed   int    // Notifications dropped by the rate limit since the last one
+	Text         string // Human-readable summary of the event
+}
+
+// WebhookNotifier is a Notifier which posts events as JSON to a webhook, e.g. of Slack or Mattermost.
+// Failed requests are retried with exponential backoff.
+type WebhookNotifier struct {
+	config   WebhookConfig
+	template *template.Template
+	hostname string
+
+	lock        sync.Mutex
+	windowStart time.Time
+	sent        int // Notifications sent in the current rate window
+	suppressed  int
+}
+
+// NewWebhookNotifier returns a WebhookNotifier, or an error if the config is invalid.
+func NewWebhookNotifier(config WebhookConfig) (*WebhookNotifier, error) {
+	if config.URL == "" {
+		return nil, errors.New("webhook URL is required")
+	}
+
+	if config.Template == "" {
+		config.Template = DefaultWebhookTemplate
+	}
+
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultWebhookMaxRetries
+	}
+
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = DefaultWebhookRetryDelay
+	}
+
+	if config.RateLimit > 0 && config.RatePeriod <= 0 {
+		return nil, errors.New("webhook rate period is required with a rate limit")
+	}
+
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(config.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &WebhookNotifier{config: config, template: tmpl, hostname: hostname}, nil
+}
+
+// Notify posts the event to the webhook unless the rate limit is exceeded.
+func (w *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	suppressed, ok := w.allow(time.Now())
+	if !ok {
+		return nil
+	}
+
+	payload := WebhookPayload{
+		Event:        event.Type.String(),
+		Process:      event.Process,
+		RestartCount: event.RestartCount,
+		Time:         event.Time,
+		Hostname:     w.hostname,
+		Suppressed:   suppressed,
+		Text:         webhookText(event, suppressed),
+	}
+	if event.Err != nil {
+		payload.Error = event.Err.Error()
+	}
+
+	var body bytes.Buffer
+	if err := w.template.Execute(&body, payload); err != nil {
+		return fmt.Errorf("failed to render webhook payload: %w", err)
+	}
+
+	delay := w.config.RetryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(ctx, body.Bytes())
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.config.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return fmt.Errorf("%w, last error: %w", ctx.Err(), err)
+		}
+	}
+}
+
+// allow reports whether a notification is within the rate limit and
+// how many notifications were dropped before it.
+func (w *WebhookNotifier) allow(now time.Time) (int, bool) {
+	if w.config.RateLimit <= 0 {
+		return 0, true
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if now.Sub(w.windowStart) >= w.config.RatePeriod {
+		w.windowStart = now
+		w.sent = 0
+	}
+
+	if w.sent >= w.config.RateLimit {
+		w.suppressed++
+		return 0, false
+	}
+
+	w.sent++
+	suppressed := w.suppressed
+	w.suppressed = 0
+
+	return suppressed, true
+}
+
+// post sends a payload and reports whether a failure is worth a retry.
+func (w *WebhookNotifier) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.config.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// webhookText returns a one-line summary of an event.
+func webhookText(event Event, suppressed int) string {
+	var text string
+	switch event.Type {
+	case EventPanic:
+		text = fmt.Sprintf("process %s panicked: %v", event.Process, event.Err)
+	case EventRestartLimitExceeded:
+		text = fmt.Sprintf("process %s exceeded its restart limit after %d restarts and is stopped",
+			event.Process, event.RestartCount)
+	case EventShutdownTimeout:
+		text = fmt.Sprintf("graceful shutdown timed out: %v", event.Err)
+	default:
+		text = fmt.Sprintf("process %s: %s", event.Process, event.Type)
+	}
+
+	if suppressed > 0 {
+		text += fmt.Sprintf(" (%d more notifications suppressed by rate limit)", suppressed)
+	}
+
+	return text
+}
@@ -0,0 +1,267 @@
+package simplevisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookServer records the request bodies it receives and replies with the given status codes in order.
+type webhookServer struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	statuses []int
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	t.Helper()
+
+	w := &webhookServer{statuses: statuses}
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		w.lock.Lock()
+		w.bodies = append(w.bodies, string(body))
+		w.headers = append(w.headers, r.Header.Clone())
+		status := http.StatusOK
+		if len(w.statuses) > 0 {
+			status, w.statuses = w.statuses[0], w.statuses[1:]
+		}
+		w.lock.Unlock()
+
+		rw.WriteHeader(status)
+	}))
+	t.Cleanup(w.Close)
+
+	return w
+}
+
+func (w *webhookServer) requests() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return append([]string(nil), w.bodies...)
+}
+
+var panicEvent = Event{
+	Type:    EventPanic,
+	Process: "consumer",
+	Time:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	Err:     &PanicError{Value: `bad "input"`},
+}
+
+func TestWebhookNotifier_DefaultPayload(t *testing.T) {
+	server := newWebhookServer(t)
+	notifier, err := NewWebhookNotifier(WebhookConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+
+	if err := notifier.Notify(context.Background(), panicEvent); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	requests := server.requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+
+	var payload map[string]string
+	if err := json.Unmarshal([]byte(requests[0]), &payload); err != nil {
+		t.Fatalf("payload is not valid JSON: %v: %s", err, requests[0])
+	}
+	if want := `process consumer panicked: panic: bad "input"`; payload["text"] != want {
+		t.Errorf("expected text %q, got %q", want, payload["text"])
+	}
+	if got := server.headers[0].Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected JSON content type, got %q", got)
+	}
+}
+
+func TestWebhookNotifier_TemplateAndHeaders(t *testing.T) {
+	server := newWebhookServer(t)
+	notifier, err := NewWebhookNotifier(WebhookConfig{
+		URL:      server.URL,
+		Template: `{"event": {{json .Event}}, "process": {{json .Process}}, "error": {{json .Error}}}`,
+		Headers:  map[string]string{"Authorization": "Bearer token"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+
+	if err := notifier.Notify(context.Background(), panicEvent); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	want := `{"event": "panic", "process": "consumer", "error": "panic: bad \"input\""}`
+	if got := server.requests()[0]; got != want {
+		t.Errorf("expected payload %s, got %s", want, got)
+	}
+	if got := server.headers[0].Get("Authorization"); got != "Bearer token" {
+		t.Errorf("expected Authorization header, got %q", got)
+	}
+}
+
+func TestWebhookNotifier_Retries(t *testing.T) {
+	tests := []struct {
+		name             string
+		statuses         []int
+		maxRetries       int
+		expectedRequests int
+		expectErr        bool
+	}{
+		{
+			name:             "retries server errors until success",
+			statuses:         []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			maxRetries:       3,
+			expectedRequests: 3,
+		},
+		{
+			name:             "gives up after max retries",
+			statuses:         []int{500, 500, 500, 500},
+			maxRetries:       2,
+			expectedRequests: 3,
+			expectErr:        true,
+		},
+		{
+			name:             "doesn't retry client errors",
+			statuses:         []int{http.StatusBadRequest},
+			maxRetries:       3,
+			expectedRequests: 1,
+			expectErr:        true,
+		},
+		{
+			name:             "negative max retries disables retries",
+			statuses:         []int{http.StatusInternalServerError},
+			maxRetries:       -1,
+			expectedRequests: 1,
+			expectErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWebhookServer(t, tt.statuses...)
+			notifier, err := NewWebhookNotifier(WebhookConfig{
+				URL:        server.URL,
+				MaxRetries: tt.maxRetries,
+				RetryDelay: time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("failed to create notifier: %v", err)
+			}
+
+			err = notifier.Notify(context.Background(), panicEvent)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if got := len(server.requests()); got != tt.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tt.expectedRequests, got)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_RetryStopsOnContextDone(t *testing.T) {
+	server := newWebhookServer(t, 500, 500, 500)
+	notifier, err := NewWebhookNotifier(WebhookConfig{URL: server.URL, RetryDelay: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := notifier.Notify(ctx, panicEvent); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestWebhookNotifier_RateLimit(t *testing.T) {
+	server := newWebhookServer(t)
+	notifier, err := NewWebhookNotifier(WebhookConfig{
+		URL:        server.URL,
+		RateLimit:  2,
+		RatePeriod: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+
+	for range 5 {
+		if err := notifier.Notify(context.Background(), panicEvent); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+	if got := len(server.requests()); got != 2 {
+		t.Fatalf("expected 2 requests within the rate limit, got %d", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if err := notifier.Notify(context.Background(), panicEvent); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	requests := server.requests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	if !strings.Contains(requests[2], "3 more notifications suppressed") {
+		t.Errorf("expected suppressed count in payload, got %s", requests[2])
+	}
+}
+
+func TestNewWebhookNotifier_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config WebhookConfig
+	}{
+		{name: "missing URL", config: WebhookConfig{}},
+		{name: "invalid template", config: WebhookConfig{URL: "http://localhost", Template: "{{.Text"}},
+		{name: "rate limit without period", config: WebhookConfig{URL: "http://localhost", RateLimit: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewWebhookNotifier(tt.config); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_WithSupervisor(t *testing.T) {
+	server := newWebhookServer(t)
+	notifier, err := NewWebhookNotifier(WebhookConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+
+	s := New(time.Second, slog.New(slog.DiscardHandler), WithNotifier(notifier))
+	s.Register("crashing", func(ctx context.Context) error {
+		panic("boom")
+	})
+	s.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for len(server.requests()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.Shutdown()
+
+	requests := server.requests()
+	if len(requests) != 1 || !strings.Contains(requests[0], "process crashing panicked: panic: boom") {
+		t.Errorf("expected panic notification, got %v", requests)
+	}
+}